@@ -0,0 +1,109 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/elastic-fray/pkg/history"
+
+	"github.com/tokopedia/tdk/go/log"
+)
+
+func compare(args []string) {
+	var (
+		results   string
+		baseline  string
+		candidate string
+		threshold float64
+		list      bool
+	)
+
+	flags := flag.NewFlagSet("compare", flag.ExitOnError)
+	flags.StringVar(&results, "results", "results", "directory the runs were saved to")
+	flags.StringVar(&baseline, "baseline", "", "id of the baseline run")
+	flags.StringVar(&candidate, "candidate", "latest", "id of the run to check, or latest")
+	flags.Float64Var(&threshold, "threshold", 10, "flag a metric that got worse by more than this percent, or percentage points for the error rate")
+	flags.BoolVar(&list, "list", false, "list the saved runs and exit")
+	flags.Parse(args)
+
+	store := history.New(history.Config{
+		Dir: results,
+	})
+
+	if list {
+		runs, err := store.List()
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		for _, run := range runs {
+			fmt.Printf("%s  %s  %s  %v\n", run.ID, run.Target.Environment, run.Target.Index, run.Versions)
+		}
+		return
+	}
+
+	if baseline == "" {
+		fmt.Fprintln(os.Stderr, "compare: -baseline is required, see -list for the saved runs")
+		os.Exit(2)
+	}
+
+	base, err := store.Load(baseline)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	cand, err := store.Load(candidate)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if cand.ID == base.ID {
+		fmt.Fprintf(os.Stderr, "compare: the candidate %s is the baseline, pass -candidate, see -list for the saved runs\n", cand.ID)
+		os.Exit(2)
+	}
+
+	regressions := store.Compare(base, cand, threshold)
+
+	fmt.Printf("baseline %s (%s), candidate %s (%s), threshold %.1f%% or points of error rate\n", base.ID, base.Revision, cand.ID, cand.Revision, threshold)
+
+	if len(regressions) == 0 {
+		fmt.Println("no regressions")
+		return
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "SCENARIO\tCLIENT\tOPERATION\tMETRIC\tBASELINE\tCANDIDATE\tCHANGE")
+
+	for _, r := range regressions {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			r.Scenario, r.Client, r.Operation, r.Metric, metric(r.Metric, r.Baseline), metric(r.Metric, r.Candidate), change(r.Metric, r.Change))
+	}
+
+	w.Flush()
+	os.Exit(1)
+}
+
+func metric(name string, value float64) string {
+	switch name {
+	case history.MetricThroughput:
+		return fmt.Sprintf("%.1f req/s", value)
+	case history.MetricErrorRate:
+		return fmt.Sprintf("%.2f%%", value*100)
+	}
+
+	return round(time.Duration(value)).String()
+}
+
+func change(name string, value *float64) string {
+	switch {
+	case value == nil:
+		return "n/a"
+	case name == history.MetricErrorRate:
+		return fmt.Sprintf("%+.1fpp", *value)
+	}
+
+	return fmt.Sprintf("%+.1f%%", *value)
+}
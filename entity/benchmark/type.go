@@ -0,0 +1,165 @@
+package benchmark
+
+import (
+	"time"
+
+	"github.com/elastic-fray/entity/elastic"
+)
+
+var (
+	Clients    = []string{elastic.ClientAPI, elastic.ClientOfficialClient}
+	Operations = []string{elastic.OperationSearch, elastic.OperationCount, elastic.OperationInsert, elastic.OperationUpdate, elastic.OperationDelete, elastic.OperationBulk}
+)
+
+type (
+	Parameter struct {
+		Name        string        `yaml:"name" json:"name"`
+		Clients     []string      `yaml:"clients" json:"clients"`
+		Operations  []Operation   `yaml:"operations" json:"operations"`
+		Iterations  int           `yaml:"iterations" json:"iterations"`
+		Duration    time.Duration `yaml:"duration" json:"duration"`
+		Warmup      time.Duration `yaml:"warmup" json:"warmup"`
+		Workers     int           `yaml:"workers" json:"workers"`
+		QPS         float64       `yaml:"qps" json:"qps"`
+		OpenLoop    bool          `yaml:"open_loop" json:"open_loop"` // keep the qps schedule, latency counts from when a request was due
+		RampUp      time.Duration `yaml:"ramp_up" json:"ramp_up"`
+		Verbose     bool          `yaml:"verbose" json:"verbose"`
+		OrderID     int64         `yaml:"order_id" json:"order_id"`
+		Seed        int64         `yaml:"seed" json:"seed"`
+		RefreshWait time.Duration `yaml:"refresh_wait" json:"refresh_wait"`
+		Faults      *Faults       `yaml:"faults" json:"faults,omitempty"`
+		Profile     bool          `yaml:"profile" json:"profile"`
+	}
+
+	Operation struct {
+		Name       string                 `yaml:"name" json:"name"`
+		Weight     int                    `yaml:"weight" json:"weight"`
+		Query      string                 `yaml:"query" json:"query"`
+		Size       int64                  `yaml:"size" json:"size"`
+		Sort       map[string]interface{} `yaml:"sort" json:"sort"`
+		PreferNode string                 `yaml:"prefer_node" json:"prefer_node"`
+		TimeRange  *TimeRange             `yaml:"time_range" json:"time_range"`
+		BatchSize  int                    `yaml:"batch_size" json:"batch_size"`
+	}
+
+	TimeRange struct {
+		From string        `yaml:"from" json:"from"`
+		To   string        `yaml:"to" json:"to"`
+		Last time.Duration `yaml:"last" json:"last"`
+	}
+
+	Faults struct {
+		Latency         time.Duration `yaml:"latency" json:"latency"`
+		Jitter          time.Duration `yaml:"jitter" json:"jitter"`
+		LatencyRate     float64       `yaml:"latency_rate" json:"latency_rate"` // all requests when 0 and latency is set
+		TooManyRequests float64       `yaml:"too_many_requests" json:"too_many_requests"`
+		Unavailable     float64       `yaml:"unavailable" json:"unavailable"`
+		Truncate        float64       `yaml:"truncate" json:"truncate"`
+		Reset           float64       `yaml:"reset" json:"reset"`
+	}
+
+	Scenarios struct {
+		Scenarios []Parameter `yaml:"scenarios"`
+	}
+
+	Result struct {
+		Scenario string                      `json:"scenario"`
+		Clients  []string                    `json:"clients,omitempty"` // in the order they ran
+		OpenLoop bool                        `json:"open_loop,omitempty"`
+		Faults   *Faults                     `json:"faults,omitempty"`
+		Injected map[string]map[string]int64 `json:"injected,omitempty"`
+		Stats    []Stats                     `json:"stats"`
+		Profiles []Profile                   `json:"profiles,omitempty"`
+	}
+
+	Stats struct {
+		Client    string        `json:"client"`
+		Operation string        `json:"operation"`
+		Count     int64         `json:"count"`
+		Errors    int64         `json:"errors"`
+		Elapsed   time.Duration `json:"elapsed"`
+		Min       time.Duration `json:"min"`
+		Mean      time.Duration `json:"mean"`
+		Max       time.Duration `json:"max"`
+		P50       time.Duration `json:"p50"`
+		P90       time.Duration `json:"p90"`
+		P99       time.Duration `json:"p99"`
+		P999      time.Duration `json:"p999"`
+	}
+
+	Profile struct {
+		Client      string   `json:"client"`
+		Requests    int64    `json:"requests"`
+		Allocs      uint64   `json:"allocs"`
+		AllocBytes  uint64   `json:"alloc_bytes"`
+		AllocsPerOp float64  `json:"allocs_per_op"`
+		BytesPerOp  float64  `json:"bytes_per_op"`
+		Files       []string `json:"files,omitempty"`
+
+		CPU           []byte `json:"-"`
+		Heap          []byte `json:"-"`
+		AllocsProfile []byte `json:"-"`
+		AllocsBase    []byte `json:"-"`
+	}
+
+	Verification struct {
+		Scenario string  `json:"scenario"`
+		Checks   []Check `json:"checks"`
+	}
+
+	Check struct {
+		Operation  string     `json:"operation"`
+		Query      string     `json:"query"`
+		Match      bool       `json:"match"`
+		Totals     []Total    `json:"totals"`
+		Error      string     `json:"error,omitempty"`
+		Mismatches []Mismatch `json:"mismatches,omitempty"`
+	}
+
+	Total struct {
+		Client string `json:"client"`
+		Total  int    `json:"total"`
+	}
+
+	Mismatch struct {
+		Client  string   `json:"client"`
+		OrderID int64    `json:"order_id"`
+		Reason  string   `json:"reason"`
+		Fields  []string `json:"fields,omitempty"`
+	}
+
+	Run struct {
+		ID         string            `json:"id"`
+		StartedAt  time.Time         `json:"started_at"`
+		Namespace  int64             `json:"namespace"` // first order id of the documents the run wrote
+		Revision   string            `json:"revision"`
+		Versions   map[string]string `json:"versions"`
+		Target     Target            `json:"target"`
+		Parameters []Parameter       `json:"parameters"`
+		Results    []Result          `json:"results"`
+	}
+
+	Target struct {
+		Environment string `json:"environment"`
+		URL         string `json:"url"`
+		Index       string `json:"index"`
+	}
+
+	Regression struct {
+		Scenario  string   `json:"scenario"`
+		Client    string   `json:"client"`
+		Operation string   `json:"operation"`
+		Metric    string   `json:"metric"`
+		Baseline  float64  `json:"baseline"`
+		Candidate float64  `json:"candidate"`
+		Change    *float64 `json:"change"` // percent, percentage points for the error rate, nil when the baseline is 0
+	}
+)
+
+func (s Stats) Throughput() float64 {
+	if s.Elapsed <= 0 {
+		return 0
+	}
+
+	return float64(s.Count) / s.Elapsed.Seconds()
+}
@@ -6,6 +6,19 @@ import (
 	"github.com/elastic-fray/entity/promo/marketplace"
 )
 
+const (
+	ClientAPI            = "api"
+	ClientOfficialClient = "officialclient"
+	ClientNetHTTP        = "nethttp"
+
+	OperationSearch = "search"
+	OperationCount  = "count"
+	OperationInsert = "insert"
+	OperationUpdate = "update"
+	OperationDelete = "delete"
+	OperationBulk   = "bulk"
+)
+
 type (
 	ElasticSearchParameter struct {
 		QueryString string
@@ -52,6 +65,10 @@ type (
 		Index BulkInsert `json:"index"`
 	}
 
+	DeleteBulk struct {
+		Delete BulkInsert `json:"delete"`
+	}
+
 	PromoOrderUsageBulkInsert struct {
 		Doc marketplace.Promo `json:"doc"`
 	}
@@ -1,8 +1,29 @@
 module github.com/elastic-fray
 
-go 1.14
+go 1.18
 
 require (
 	github.com/elastic/go-elasticsearch/v7 v7.5.1-0.20200508111001-3c036aa259b3
 	github.com/ooyala/go-dogstatsd v0.0.0-20140922214459-23f2a1659b02
+	github.com/prometheus/client_golang v1.6.0
+	github.com/robfig/cron/v3 v3.0.1
+	go.opentelemetry.io/otel v1.14.0
+	go.opentelemetry.io/otel/exporters/stdout/stdouttrace v1.14.0
+	go.opentelemetry.io/otel/sdk v1.14.0
+	go.opentelemetry.io/otel/trace v1.14.0
+	gopkg.in/yaml.v2 v2.3.0
+)
+
+require (
+	github.com/beorn7/perks v1.0.1 // indirect
+	github.com/cespare/xxhash/v2 v2.1.1 // indirect
+	github.com/go-logr/logr v1.2.3 // indirect
+	github.com/go-logr/stdr v1.2.2 // indirect
+	github.com/golang/protobuf v1.4.0 // indirect
+	github.com/matttproud/golang_protobuf_extensions v1.0.1 // indirect
+	github.com/prometheus/client_model v0.2.0 // indirect
+	github.com/prometheus/common v0.9.1 // indirect
+	github.com/prometheus/procfs v0.0.11 // indirect
+	golang.org/x/sys v0.7.0 // indirect
+	google.golang.org/protobuf v1.21.0 // indirect
 )
@@ -1,23 +1,43 @@
 package main
 
 import (
-	"bytes"
 	"context"
-	"encoding/json"
+	"flag"
 	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"runtime"
+	"runtime/debug"
 	"strings"
+	"syscall"
 	"time"
 
 	"github.com/ooyala/go-dogstatsd"
 
-	"github.com/elastic-fray/entity/elastic"
-	"github.com/elastic-fray/entity/promo/marketplace"
+	"github.com/elastic-fray/entity/benchmark"
+	"github.com/elastic-fray/pkg/dashboard"
+	"github.com/elastic-fray/pkg/fault"
 	"github.com/elastic-fray/pkg/monitor"
+	"github.com/elastic-fray/pkg/monitor/buffer"
+	"github.com/elastic-fray/pkg/monitor/memory"
+	"github.com/elastic-fray/pkg/monitor/prometheus"
+	"github.com/elastic-fray/pkg/replay"
+	"github.com/elastic-fray/pkg/scenario"
+	"github.com/elastic-fray/pkg/tracing"
+	"github.com/elastic-fray/pkg/tracker"
 	"github.com/elastic-fray/pkg/utils"
-	"github.com/elastic-fray/usecase/elastic/api"
-	"github.com/elastic-fray/usecase/elastic/officialclient"
+	"github.com/elastic-fray/usecase/driver"
+
+	benchmarkUsecase "github.com/elastic-fray/usecase/benchmark"
 
 	"github.com/tokopedia/tdk/go/log"
+
+	"github.com/tokopedia/sauron/src/elastic"
 )
 
 var (
@@ -26,7 +46,21 @@ var (
 	DatadogClient *dogstatsd.Client
 	Location      *time.Location
 	Monitor       monitor.Method
+	Metrics       memory.Method
+	Buffer        buffer.Method
+	Fixtures      []replay.Method
+	Faults        fault.Method
+	Transports    map[string]http.RoundTripper
+	Drivers       map[string]driver.Method
+	Dashboard     dashboard.Method
+	Tracker       tracker.Method
+	Tracing       tracing.Method
 	err           error
+
+	fixtureDirs struct {
+		record string
+		replay string
+	}
 )
 
 func init() {
@@ -40,219 +74,412 @@ func init() {
 		log.Fatal(err)
 	}
 
-	Config = utils.Config{
-		Server: utils.ServerConfig{
-			Environment: "", // TODO: please fill
-		},
-		Datadog: utils.DatadogConfig{
-			Connection: "", // TODO: please fill
-		},
-		ElasticSearch: utils.ElasticSearchConfig{
-			URL: "", // TODO: please fill
-		},
-	}
-
-	DatadogClient, err = dogstatsd.New(Config.Datadog.Connection)
+	Location, err = time.LoadLocation("Asia/Jakarta")
 	if err != nil {
 		log.Fatal(err)
 	}
-	DatadogClient.Namespace = "elastic-fray."
-	DatadogClient.Tags = append(DatadogClient.Tags, "env:"+Config.Server.Environment)
+}
 
-	Location, err = time.LoadLocation("Asia/Jakarta")
-	if err != nil {
-		log.Fatal(err)
+func main() {
+	var cancel context.CancelFunc
+	Context, cancel = context.WithCancel(Context)
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+
+	go func() {
+		<-signals
+		log.Info("interrupted, stopping and deleting the test documents, send again to exit now")
+		cancel()
+
+		<-signals
+		os.Exit(130)
+	}()
+
+	command, args := "run", os.Args[1:]
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		command, args = args[0], args[1:]
 	}
 
-	Monitor = monitor.New(monitor.Config{
-		Datadog: DatadogClient,
-	})
+	switch command {
+	case "run":
+		run(args)
+	case "verify":
+		verify(args)
+	case "compare":
+		compare(args)
+	case "schedule":
+		schedule(args)
+	default:
+		fmt.Fprintf(os.Stderr, "unknown command %q, available commands: run, verify, compare, schedule\n", command)
+		os.Exit(2)
+	}
 }
 
-func main() {
-	// currentTime := time.Now()
+type workloadFlags struct {
+	parameter  benchmark.Parameter
+	operation  benchmark.Operation
+	clients    string
+	operations string
+	scenarios  string
+}
 
-	// for i := currentTime.Hour(); i < 23; {
-	// Elastic API
-	processElasticAPI()
+func newWorkloadFlags(flags *flag.FlagSet, operations []string) *workloadFlags {
+	w := &workloadFlags{}
 
-	// Elastic Official Client
-	processElasticOfficialClient()
-	// }
+	flags.StringVar(&w.scenarios, "scenario", "", "comma separated YAML or JSON scenario files, the other flags fill what a scenario leaves empty")
+	flags.StringVar(&w.clients, "client", strings.Join(benchmark.Clients, ","), "comma separated clients to benchmark: "+strings.Join(driver.Names(), ", "))
+	flags.StringVar(&w.operations, "operations", strings.Join(operations, ","), "comma separated operations to run: "+strings.Join(benchmark.Operations, ", "))
+	flags.StringVar(&w.operation.Query, "query", "source:marketplace", "query string for search, count")
+	flags.Int64Var(&w.operation.Size, "size", 0, "maximum number of documents a search returns, 0 for the client default")
+	flags.Int64Var(&w.parameter.OrderID, "order-id", 0, "order id used by insert, update, delete, bulk uses the following ids; 0 for the start of a namespace of the run")
+	flags.IntVar(&w.operation.BatchSize, "bulk-size", 2, "number of documents per bulk request")
+	flags.Int64Var(&w.parameter.Seed, "seed", 1, "seed of the generated documents, the same seed and order id give the same document")
+
+	return w
 }
 
-func processElasticAPI() {
-	defer Monitor.SetHistogram(time.Now(), "handler.elastic.api.get.promo.order.usage", nil)
+func (w *workloadFlags) load() []benchmark.Parameter {
+	parameter := w.parameter
+	parameter.Name = "default"
+	parameter.Clients = splitList(w.clients)
 
-	elasticAPI := api.New(api.Config{
-		Config:   Config,
-		Datadog:  DatadogClient,
-		Location: Location,
-		Monitor:  Monitor,
-	})
+	for _, name := range splitList(w.operations) {
+		o := w.operation
+		o.Name = name
+		o.Weight = 1
 
-	searchResp, err := elasticAPI.GetPromoOrderUsage(Context, elastic.ElasticSearchParameter{
-		QueryString: "source:marketplace",
-		Source:      "api.benchmark",
-	})
-	if err != nil {
-		log.Error(err)
+		parameter.Operations = append(parameter.Operations, o)
 	}
 
-	fmt.Println("API Search - Total Result: ", len(searchResp))
-
-	countResp, err := elasticAPI.CountPromoOrderUsage(Context, "source:marketplace")
-	if err != nil {
-		log.Error(err)
+	if w.operation.BatchSize < 1 || w.operation.BatchSize >= tracker.Size {
+		log.Fatalf("-bulk-size %d is not between 1 and %d, the documents have to fit in the namespace of the run", w.operation.BatchSize, tracker.Size-1)
 	}
 
-	fmt.Println("API Count - Total Result: ", countResp)
+	files := splitList(w.scenarios)
+	if len(files) == 0 {
+		return []benchmark.Parameter{parameter}
+	}
 
-	if err = elasticAPI.InsertPromoOrderUsage(Context, marketplace.Promo{
-		OrderID: 69696969,
-	}); err != nil {
-		log.Error(err)
+	var (
+		parameters []benchmark.Parameter
+		loader     = scenario.New(scenario.Config{
+			Defaults:  parameter,
+			Operation: w.operation,
+			Clients:   driver.Names(),
+		})
+	)
+
+	for _, file := range files {
+		loaded, err := loader.Load(file)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		parameters = append(parameters, loaded...)
 	}
 
-	if err = elasticAPI.UpdatePromoOrderUsage(Context, marketplace.Promo{
-		OrderID: 69696969,
-	}); err != nil {
-		log.Error(err)
+	return parameters
+}
+
+func clients(parameters []benchmark.Parameter) []string {
+	var (
+		list []string
+		seen = make(map[string]bool)
+	)
+
+	for _, parameter := range parameters {
+		for _, client := range parameter.Clients {
+			if !seen[client] {
+				seen[client] = true
+				list = append(list, client)
+			}
+		}
 	}
 
-	time.Sleep(1000000000) // 1s, let give it time
+	return list
+}
 
-	deleteResp, err := elasticAPI.DeletePromoOrderUsage(Context, "order_id:69696969")
-	if err != nil {
-		log.Error(err)
+func hasFaults(parameters []benchmark.Parameter) bool {
+	for _, parameter := range parameters {
+		if parameter.Faults != nil {
+			return true
+		}
 	}
 
-	fmt.Println("API Delete - Status: ", deleteResp)
+	return false
+}
 
-	// TODO: move to usecase
-	var buffer bytes.Buffer
+func configFlags(flags *flag.FlagSet) {
+	flags.StringVar(&Config.Server.Environment, "env", "", "server environment, used as the index prefix")
+	flags.StringVar(&Config.Datadog.Connection, "datadog", "", "datadog statsd address")
+	flags.StringVar(&Config.Monitor.Backend, "monitor", monitor.BackendDatadog, "metrics backend: "+monitor.BackendDatadog+", "+monitor.BackendPrometheus+", or "+monitor.BackendMemory+" to print a summary after each run")
+	flags.IntVar(&Config.Monitor.QueueSize, "monitor-queue", 0, "metrics queued for the backend and sent every -monitor-flush, a full queue drops them, 0 to send each as it is recorded")
+	flags.DurationVar(&Config.Monitor.FlushInterval, "monitor-flush", time.Second, "interval the queued metrics are sent at")
+	flags.StringVar(&Config.Prometheus.Address, "prometheus", ":9464", "address /metrics is served on with -monitor prometheus")
+	flags.StringVar(&Config.Tracing.File, "trace", "", "write a span of every Elasticsearch call to this file as JSON lines, - for stdout")
+	flags.StringVar(&Config.ElasticSearch.URL, "url", "", "elasticsearch url")
+	flags.StringVar(&Config.ElasticSearch.Index, "index", elastic.ConstElasticSearchIndexPromoOrderUsage, "target index, without the environment prefix")
+	flags.StringVar(&fixtureDirs.record, "record", "", "record the traffic of each client to fixture files in this directory")
+	flags.StringVar(&fixtureDirs.replay, "replay", "", "answer from the fixture files in this directory instead of the cluster")
+}
 
-	index, _ := json.Marshal(elastic.IndexBulkInsert{
-		Index: elastic.BulkInsert{
-			Index: "staging-promo-order-usage",
-			Type:  "order",
-			ID:    "66666666",
-		},
-	})
-	data, _ := json.Marshal(elastic.PromoOrderUsageBulkInsert{
-		Doc: marketplace.Promo{
-			OrderID: 66666666,
-		},
-	})
-	buffer.WriteString(fmt.Sprintf("%s\n%s\n", index, data))
-
-	index, _ = json.Marshal(elastic.IndexBulkInsert{
-		Index: elastic.BulkInsert{
-			Index: "staging-promo-order-usage",
-			Type:  "order",
-			ID:    "99999999",
-		},
-	})
-	data, _ = json.Marshal(elastic.PromoOrderUsageBulkInsert{
-		Doc: marketplace.Promo{
-			OrderID: 99999999,
-		},
-	})
-	buffer.WriteString(fmt.Sprintf("%s\n%s\n", index, data))
+func setup(clients []string, faults bool) {
+	setupMonitor()
+	setupTracing()
+
+	Tracker = tracker.New(tracker.Config{})
+
+	setupTransports(clients, faults)
+	setupDrivers(clients)
+}
+
+func setupMonitor() {
+	address := Config.Datadog.Connection
+	if address == "" && Config.Monitor.Backend != monitor.BackendDatadog {
+		address = "127.0.0.1:8125" // the agent default, the packets go nowhere without one
+	}
 
-	bulkResp, err := elasticAPI.BulkPromoOrderUsage(Context, Config.ElasticSearch.URL, buffer.String())
+	DatadogClient, err = dogstatsd.New(address)
 	if err != nil {
-		log.Error(err)
+		log.Fatal(err)
 	}
+	DatadogClient.Namespace = "elastic-fray."
+	DatadogClient.Tags = append(DatadogClient.Tags, "env:"+Config.Server.Environment)
 
-	fmt.Println("API Bulk - Status: ", bulkResp)
+	switch Config.Monitor.Backend {
+	case monitor.BackendDatadog:
+		Monitor = monitor.New(monitor.Config{
+			Datadog: DatadogClient,
+		})
+	case monitor.BackendPrometheus:
+		metrics := prometheus.New(prometheus.Config{
+			Namespace: "elastic-fray",
+			Tags:      []string{"env:" + Config.Server.Environment},
+		})
+		serveMetrics(metrics.Handler())
+		Monitor = metrics
+	case monitor.BackendMemory:
+		Metrics = memory.New()
+		Monitor = Metrics
+	default:
+		log.Fatalf("unknown monitor %q, available: %s, %s, %s", Config.Monitor.Backend, monitor.BackendDatadog, monitor.BackendPrometheus, monitor.BackendMemory)
+	}
+
+	// so that sending the metrics does not slow down the calls they measure
+	if Config.Monitor.QueueSize > 0 {
+		Buffer = buffer.New(buffer.Config{
+			Monitor:  Monitor,
+			Size:     Config.Monitor.QueueSize,
+			Interval: Config.Monitor.FlushInterval,
+		})
+		Monitor = Buffer
+	}
 }
 
-func processElasticOfficialClient() {
-	defer Monitor.SetHistogram(time.Now(), "handler.elastic.official.client.get.promo.order.usage", nil)
+func setupTracing() {
+	if Config.Tracing.File == "" {
+		return
+	}
 
-	elasticOfficial, err := officialclient.New(officialclient.Config{
-		Config:  Config,
-		Monitor: Monitor,
+	Tracing, err = tracing.New(tracing.Config{
+		File:    Config.Tracing.File,
+		Service: "elastic-fray",
 	})
 	if err != nil {
 		log.Fatal(err)
 	}
+}
 
-	searchResp, err := elasticOfficial.GetPromoOrderUsage(Context, elastic.ElasticSearchParameter{
-		QueryString: "source:marketplace",
-		Source:      "officialclient.benchmark",
-	})
+func serveMetrics(handler http.Handler) {
+	listener, err := net.Listen("tcp", Config.Prometheus.Address)
 	if err != nil {
-		log.Error(err)
+		log.Fatal(err)
 	}
 
-	fmt.Println("Official Client Search - Total Result: ", len(searchResp))
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", handler)
 
-	countResp, err := elasticOfficial.CountPromoOrderUsage(Context, elastic.ElasticSearchParameter{
-		QueryString: "source:marketplace",
-		Source:      "officialclient.benchmark",
-	})
-	if err != nil {
-		log.Error(err)
+	go http.Serve(listener, mux)
+
+	log.Infof("serving prometheus metrics on http://%s/metrics", listener.Addr())
+}
+
+func setupTransports(clients []string, faults bool) {
+	Transports = make(map[string]http.RoundTripper)
+
+	mode, dir := replay.ModeRecord, fixtureDirs.record
+	if fixtureDirs.replay != "" {
+		if dir != "" {
+			log.Fatal("use either -record or -replay")
+		}
+		mode, dir = replay.ModeReplay, fixtureDirs.replay
 	}
 
-	fmt.Println("Official Client Count - Total Result: ", countResp)
+	if faults {
+		Faults = fault.New(fault.Config{})
+	}
 
-	if err = elasticOfficial.InsertPromoOrderUsage(Context, marketplace.Promo{
-		OrderID: 96969696,
-	}); err != nil {
-		log.Error(err)
+	for _, client := range clients {
+		var transport http.RoundTripper
+
+		if dir != "" {
+			fixture, err := replay.New(replay.Config{
+				Mode: mode,
+				File: filepath.Join(dir, client+".json"),
+			})
+			if err != nil {
+				log.Fatal(err)
+			}
+
+			Fixtures = append(Fixtures, fixture)
+			transport = fixture
+		}
+
+		if faults {
+			transport = Faults.Wrap(transport)
+		}
+
+		if transport != nil {
+			Transports[client] = transport
+		}
 	}
+}
 
-	if err = elasticOfficial.UpdatePromoOrderUsage(Context, marketplace.Promo{
-		OrderID: 96969696,
-	}); err != nil {
-		log.Error(err)
+func setupDrivers(clients []string) {
+	Drivers = make(map[string]driver.Method)
+
+	for _, client := range clients {
+		d, err := driver.Get(client)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		Drivers[client], err = d.Open(driver.Config{
+			Config:    Config,
+			Datadog:   DatadogClient,
+			Location:  Location,
+			Monitor:   Monitor,
+			Transport: Transports[client],
+		})
+		if err != nil {
+			log.Fatal(err)
+		}
 	}
+}
 
-	time.Sleep(1000000000) // 1s, let give it time
+func saveFixtures() {
+	for _, fixture := range Fixtures {
+		if err := fixture.Save(); err != nil {
+			log.Error(err)
+		}
+	}
 
-	deleteResp, err := elasticOfficial.DeletePromoOrderUsage(Context, "96969696")
-	if err != nil {
+	if fixtureDirs.record != "" && len(Fixtures) > 0 {
+		fmt.Printf("\nrecorded fixtures to %s\n", fixtureDirs.record)
+	}
+}
+
+func shutdown() {
+	if Buffer != nil {
+		Buffer.Close()
+
+		if n := Buffer.Dropped(); n > 0 {
+			log.Errorf("dropped %d metrics, the queue of -monitor-queue was full", n)
+		}
+	}
+
+	if err := driver.Close(); err != nil {
 		log.Error(err)
 	}
 
-	fmt.Println("Official Client Delete - Status:", deleteResp)
+	saveFixtures()
 
-	// TODO: move to usecase
-	var buffer bytes.Buffer
+	if Tracing != nil {
+		ctx, cancel := context.WithTimeout(Context, 10*time.Second)
+		defer cancel()
 
-	index, _ := json.Marshal(elastic.IndexBulkInsert{
-		Index: elastic.BulkInsert{
-			Index: "staging-promo-order-usage",
-			Type:  "order",
-			ID:    "66666666",
-		},
-	})
-	data, _ := json.Marshal(elastic.PromoOrderUsageBulkInsert{
-		Doc: marketplace.Promo{
-			OrderID: 66666666,
-		},
-	})
-	buffer.WriteString(fmt.Sprintf("%s\n%s\n", index, data))
-
-	index, _ = json.Marshal(elastic.IndexBulkInsert{
-		Index: elastic.BulkInsert{
-			Index: "staging-promo-order-usage",
-			Type:  "order",
-			ID:    "99999999",
-		},
-	})
-	data, _ = json.Marshal(elastic.PromoOrderUsageBulkInsert{
-		Doc: marketplace.Promo{
-			OrderID: 99999999,
-		},
+		if err := Tracing.Shutdown(ctx); err != nil {
+			log.Error(err)
+		}
+	}
+}
+
+func flushMetrics() {
+	if Buffer != nil {
+		Buffer.Flush()
+	}
+}
+
+func newBenchmark() benchmarkUsecase.Method {
+	return benchmarkUsecase.New(benchmarkUsecase.Config{
+		Config:    Config,
+		Location:  Location,
+		Monitor:   Monitor,
+		Drivers:   Drivers,
+		Faults:    Faults,
+		Dashboard: Dashboard,
+		Tracker:   Tracker,
 	})
-	buffer.WriteString(fmt.Sprintf("%s\n%s\n", index, data))
+}
 
-	err = elasticOfficial.BulkPromoOrderUsage(Context, strings.NewReader(buffer.String()))
+func revision() string {
+	out, err := exec.Command("git", "describe", "--always", "--dirty").Output()
 	if err != nil {
-		log.Error(err)
+		return ""
+	}
+
+	return strings.TrimSpace(string(out))
+}
+
+func versions() map[string]string {
+	v := map[string]string{
+		"go": runtime.Version(),
+	}
+
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return v
 	}
+
+	for _, dep := range info.Deps {
+		switch {
+		case strings.HasPrefix(dep.Path, "github.com/elastic/go-elasticsearch"),
+			strings.HasPrefix(dep.Path, "github.com/tokopedia/sauron"):
+			version := dep.Version
+			if dep.Replace != nil {
+				version = dep.Replace.Path + " " + dep.Replace.Version
+			}
+			v[dep.Path] = strings.TrimSpace(version)
+		}
+	}
+
+	return v
+}
+
+func target() benchmark.Target {
+	address := Config.ElasticSearch.URL
+
+	if u, err := url.Parse(address); err == nil {
+		u.User = nil // keep credentials out of the results
+		address = u.String()
+	}
+
+	return benchmark.Target{
+		Environment: Config.Server.Environment,
+		URL:         address,
+		Index:       Config.ElasticSearch.Index,
+	}
+}
+
+func splitList(s string) []string {
+	var list []string
+
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+
+	return list
 }
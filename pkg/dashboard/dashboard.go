@@ -0,0 +1,165 @@
+package dashboard
+
+import (
+	"fmt"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/elastic-fray/entity/benchmark"
+)
+
+func New(c Config) Method {
+	if c.Interval <= 0 {
+		c.Interval = time.Second
+	}
+
+	return Module{
+		config: c,
+		state:  &state{},
+	}
+}
+
+func (m Module) Watch(phase Phase) {
+	s := m.state
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	now := time.Now()
+
+	phase.Recorder.Interval() // the interval starts with the phase
+	s.phase, s.started, s.last = &phase, now, now
+
+	if s.stop == nil {
+		s.stop, s.done = make(chan struct{}), make(chan struct{})
+		go m.refresh(s.stop, s.done)
+	}
+}
+
+func (m Module) Stop() {
+	s := m.state
+	s.mutex.Lock()
+	stop, done := s.stop, s.done
+	s.stop, s.done = nil, nil
+	s.mutex.Unlock()
+
+	if stop == nil {
+		return
+	}
+
+	close(stop)
+	<-done
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	m.erase()
+	s.phase = nil
+}
+
+func (m Module) refresh(stop, done chan struct{}) {
+	defer close(done)
+
+	ticker := time.NewTicker(m.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case now := <-ticker.C:
+			m.state.mutex.Lock()
+			m.draw(now)
+			m.state.mutex.Unlock()
+		case <-stop:
+			return
+		}
+	}
+}
+
+func (m Module) draw(now time.Time) {
+	s := m.state
+	if s.phase == nil {
+		return
+	}
+
+	view := render(*s.phase, now.Sub(s.started), now.Sub(s.last))
+	s.last = now
+
+	m.erase()
+	fmt.Fprint(m.config.Writer, view)
+	s.lines = strings.Count(view, "\n")
+}
+
+func (m Module) erase() {
+	if m.state.lines > 0 {
+		fmt.Fprintf(m.config.Writer, "\033[%dA\033[J", m.state.lines)
+		m.state.lines = 0
+	}
+}
+
+func render(phase Phase, elapsed, interval time.Duration) string {
+	var (
+		b       strings.Builder
+		totals  = phase.Recorder.Snapshot()
+		current = make(map[string]benchmark.Stats)
+		done    int64
+	)
+
+	for _, s := range phase.Recorder.Interval() {
+		current[s.Client+"/"+s.Operation] = s
+	}
+
+	for _, s := range totals {
+		if s.Client == phase.Client {
+			done += s.Count
+		}
+	}
+
+	fmt.Fprintf(&b, "%s: %s %s, %s elapsed, %s\n",
+		phase.Scenario, phase.Client, phase.Name, clock(elapsed), remaining(phase, elapsed, done))
+
+	w := tabwriter.NewWriter(&b, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(w, "CLIENT\tOPERATION\tCOUNT\tERRORS\tREQ/S\tP50\tP99\t")
+
+	for _, total := range totals {
+		var (
+			c        = current[total.Client+"/"+total.Operation]
+			rate     = "-"
+			p50, p99 = "-", "-"
+		)
+
+		if c.Count > 0 {
+			rate = fmt.Sprintf("%.1f", float64(c.Count)/interval.Seconds())
+			p50, p99 = c.P50.Round(time.Microsecond).String(), c.P99.Round(time.Microsecond).String()
+		}
+
+		fmt.Fprintf(w, "%s\t%s\t%d\t%d\t%s\t%s\t%s\t\n",
+			total.Client, total.Operation, total.Count, total.Errors, rate, p50, p99)
+	}
+
+	w.Flush()
+
+	return b.String()
+}
+
+func remaining(phase Phase, elapsed time.Duration, done int64) string {
+	switch {
+	case phase.Duration > 0:
+		left := phase.Duration - elapsed
+		if left < 0 {
+			left = 0
+		}
+		return clock(left) + " left"
+	case phase.Requests > 0 && done > 0:
+		left := time.Duration(float64(elapsed) * float64(phase.Requests-done) / float64(done))
+		return fmt.Sprintf("%d/%d requests, about %s left", done, phase.Requests, clock(left))
+	case phase.Requests > 0:
+		return fmt.Sprintf("0/%d requests", phase.Requests)
+	}
+
+	return "until stopped"
+}
+
+func clock(d time.Duration) string {
+	d = d.Round(time.Second)
+	return fmt.Sprintf("%02d:%02d:%02d", int(d.Hours()), int(d.Minutes())%60, int(d.Seconds())%60)
+}
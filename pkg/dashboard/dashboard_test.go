@@ -0,0 +1,72 @@
+package dashboard
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/elastic-fray/pkg/recorder"
+)
+
+func TestRender(t *testing.T) {
+	latencies := recorder.New()
+	for i := 0; i < 10; i++ {
+		latencies.Record("api", "search", 2*time.Millisecond, nil)
+	}
+	latencies.Record("api", "count", time.Millisecond, errors.New("timeout"))
+	latencies.Interval() // count falls out of the current interval
+
+	for i := 0; i < 4; i++ {
+		latencies.Record("api", "search", 4*time.Millisecond, nil)
+	}
+
+	view := render(Phase{
+		Scenario: "default",
+		Client:   "api",
+		Name:     "measurement",
+		Recorder: latencies,
+		Requests: 30,
+	}, 15*time.Second, 2*time.Second)
+
+	for _, want := range []string{
+		"default: api measurement, 00:00:15 elapsed, 15/30 requests, about 00:00:15 left\n",
+		"api      count      1       1      -    -    -",
+		"api     search     14       0    2.0  4ms  4ms",
+	} {
+		if !strings.Contains(view, want) {
+			t.Errorf("view misses %q:\n%s", want, view)
+		}
+	}
+}
+
+func TestStopErasesView(t *testing.T) {
+	var (
+		out       bytes.Buffer
+		latencies = recorder.New()
+		m         = New(Config{Writer: &out, Interval: 10 * time.Millisecond})
+	)
+
+	latencies.Record("api", "search", time.Millisecond, nil)
+	m.Watch(Phase{Scenario: "default", Client: "api", Name: "warmup", Recorder: latencies, Duration: time.Minute})
+
+	time.Sleep(35 * time.Millisecond)
+	m.Stop()
+
+	view := out.String()
+	if !strings.Contains(view, "00:01:00 left") && !strings.Contains(view, "00:00:59 left") {
+		t.Errorf("view misses the time left:\n%s", view)
+	}
+
+	// every draw after the first and the stop erase the three lines drawn
+	if !strings.HasSuffix(view, "\033[3A\033[J") {
+		t.Errorf("view not erased at stop: %q", view)
+	}
+
+	out.Reset()
+	time.Sleep(20 * time.Millisecond)
+	if out.Len() > 0 {
+		t.Errorf("drawn after stop: %q", out.String())
+	}
+}
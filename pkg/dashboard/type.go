@@ -0,0 +1,47 @@
+package dashboard
+
+import (
+	"io"
+	"sync"
+	"time"
+
+	"github.com/elastic-fray/pkg/recorder"
+)
+
+type (
+	Method interface {
+		Watch(phase Phase)
+		Stop()
+	}
+)
+
+type (
+	Config struct {
+		Writer   io.Writer     // a terminal, the view is redrawn in place
+		Interval time.Duration // 1s when 0
+	}
+
+	Module struct {
+		config Config
+		state  *state
+	}
+
+	Phase struct {
+		Scenario string
+		Client   string
+		Name     string
+		Recorder recorder.Method
+		Duration time.Duration // planned length, 0 when bound by Requests
+		Requests int64         // planned requests of Client, 0 when bound by Duration
+	}
+
+	state struct {
+		mutex   sync.Mutex
+		phase   *Phase
+		started time.Time
+		last    time.Time // of the previous refresh
+		lines   int       // drawn by the previous refresh
+		stop    chan struct{}
+		done    chan struct{}
+	}
+)
@@ -5,6 +5,8 @@ import (
 	"encoding/json"
 	"sync"
 
+	"github.com/elastic-fray/pkg/tracing"
+
 	"github.com/tokopedia/tdk/go/log"
 
 	"github.com/tokopedia/sauron/src/elastic"
@@ -41,27 +43,45 @@ func New(c Config) Method {
 	return m
 }
 
-func (m Module) Search(ctx context.Context, so *elastic.SearchOption) error {
+func (m Module) Search(ctx context.Context, so *elastic.SearchOption) (err error) {
+	ctx, span := tracing.StartRequest(ctx, "sauron.Search", tracing.AttributeIndex.String(so.Index))
+	defer func() { tracing.End(ctx, span, err) }()
+
 	return m.elastic.Search(so)
 }
 
-func (m Module) Count(ctx context.Context, so *elastic.SearchOption) (int, error) {
+func (m Module) Count(ctx context.Context, so *elastic.SearchOption) (count int, err error) {
+	ctx, span := tracing.StartRequest(ctx, "sauron.Count", tracing.AttributeIndex.String(so.Index))
+	defer func() { tracing.End(ctx, span, err) }()
+
 	return m.elastic.Count(so)
 }
 
-func (m Module) Insert(ctx context.Context, io *elastic.InsertOption) error {
+func (m Module) Insert(ctx context.Context, io *elastic.InsertOption) (err error) {
+	ctx, span := tracing.StartRequest(ctx, "sauron.Insert", tracing.AttributeIndex.String(io.Index), tracing.AttributeDocumentID.String(io.ID))
+	defer func() { tracing.End(ctx, span, err) }()
+
 	return m.elastic.Insert(io)
 }
 
-func (m Module) Update(ctx context.Context, io *elastic.InsertOption) error {
+func (m Module) Update(ctx context.Context, io *elastic.InsertOption) (err error) {
+	ctx, span := tracing.StartRequest(ctx, "sauron.Update", tracing.AttributeIndex.String(io.Index), tracing.AttributeDocumentID.String(io.ID))
+	defer func() { tracing.End(ctx, span, err) }()
+
 	return m.elastic.Update(io)
 }
 
-func (m Module) Delete(ctx context.Context, do *elastic.DeleteOption) (elastic.ElasticSearchDeleteResponse, error) {
+func (m Module) Delete(ctx context.Context, do *elastic.DeleteOption) (resp elastic.ElasticSearchDeleteResponse, err error) {
+	ctx, span := tracing.StartRequest(ctx, "sauron.DeleteByQuery", tracing.AttributeIndex.String(do.Index))
+	defer func() { tracing.End(ctx, span, err) }()
+
 	return m.elastic.Delete(do)
 }
 
-func (m Module) Bulk(ctx context.Context, url, input string) (bool, error) {
+func (m Module) Bulk(ctx context.Context, url, input string) (created bool, err error) {
+	ctx, span := tracing.StartRequest(ctx, "sauron.Bulk")
+	defer func() { tracing.End(ctx, span, err) }()
+
 	type response struct {
 		Created bool `json:"created"`
 	}
@@ -0,0 +1,153 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ooyala/go-dogstatsd"
+
+	"github.com/elastic-fray/entity/promo/marketplace"
+	"github.com/elastic-fray/pkg/elastic/fake"
+	"github.com/elastic-fray/pkg/utils"
+
+	"github.com/tokopedia/sauron/src/elastic"
+)
+
+const testIndex = "promo-order-usage"
+
+var (
+	testModule Method
+	testServer fake.Method
+)
+
+type searchResponse struct {
+	Hits struct {
+		Hits []struct {
+			ID     string            `json:"_id"`
+			Source marketplace.Promo `json:"_source"`
+		} `json:"hits"`
+	} `json:"hits"`
+}
+
+func TestMain(m *testing.M) {
+	testServer = fake.New(fake.Config{})
+
+	datadog, err := dogstatsd.New("127.0.0.1:8125")
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	testModule = New(Config{
+		Config: utils.Config{
+			Server: utils.ServerConfig{
+				Environment: "staging",
+			},
+			ElasticSearch: utils.ElasticSearchConfig{
+				URL: testServer.URL(),
+			},
+		},
+		Datadog:  datadog,
+		Location: time.UTC,
+	})
+
+	code := m.Run()
+	testServer.Close()
+	os.Exit(code)
+}
+
+func queryString(query string) elastic.Query {
+	return elastic.Query{
+		QueryString: map[string]interface{}{"query": query},
+	}
+}
+
+func TestDocumentLifecycle(t *testing.T) {
+	ctx := context.Background()
+	testServer.Reset()
+
+	for _, promo := range []marketplace.Promo{
+		{OrderID: 69696969, Source: "marketplace", Platform: "android"},
+		{OrderID: 96969696, Source: "marketplace", Platform: "ios"},
+		{OrderID: 66666666, Source: "digital", Platform: "android"},
+	} {
+		if err := testModule.Insert(ctx, &elastic.InsertOption{
+			URL:         testServer.URL(),
+			Environment: true,
+			Index:       testIndex,
+			Type:        "order",
+			ID:          fmt.Sprint(promo.OrderID),
+			Data:        promo,
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var resp searchResponse
+	if err := testModule.Search(ctx, &elastic.SearchOption{
+		URL:         testServer.URL(),
+		Environment: true,
+		Index:       testIndex,
+		Input:       queryString("source:marketplace AND NOT platform:ios"),
+		Output:      &resp,
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(resp.Hits.Hits) != 1 || resp.Hits.Hits[0].ID != "69696969" {
+		t.Fatalf("search = %+v, want order 69696969", resp.Hits.Hits)
+	}
+
+	count, err := testModule.Count(ctx, &elastic.SearchOption{
+		URL:         testServer.URL(),
+		Environment: true,
+		Index:       testIndex,
+		Input:       queryString("source:marketplace"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 2 {
+		t.Errorf("count = %d, want 2", count)
+	}
+
+	deleted, err := testModule.Delete(ctx, &elastic.DeleteOption{
+		URL:         testServer.URL(),
+		Environment: true,
+		Index:       testIndex,
+		Type:        "order",
+		Query:       queryString("order_id:69696969 OR order_id:96969696"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if deleted.Deleted != 2 {
+		t.Errorf("deleted = %d, want 2", deleted.Deleted)
+	}
+
+	if n := testServer.Count("staging-" + testIndex); n != 1 {
+		t.Errorf("documents left = %d, want 1", n)
+	}
+}
+
+func TestBulk(t *testing.T) {
+	testServer.Reset()
+
+	var body strings.Builder
+	for i := 1; i <= 3; i++ {
+		fmt.Fprintf(&body, `{"index":{"_index":"staging-%s","_type":"order","_id":"%d"}}`+"\n", testIndex, i)
+		fmt.Fprintf(&body, `{"order_id":%d,"source":"marketplace"}`+"\n", i)
+	}
+
+	if _, err := testModule.Bulk(context.Background(), testServer.URL(), body.String()); err != nil {
+		t.Fatal(err)
+	}
+
+	if n := testServer.Count("staging-" + testIndex); n != 3 {
+		t.Errorf("documents after bulk = %d, want 3", n)
+	}
+}
@@ -0,0 +1,564 @@
+package fake
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const defaultSize = 10
+
+var shards = map[string]interface{}{"total": 1, "successful": 1, "skipped": 0, "failed": 0}
+
+func New(c Config) Method {
+	if c.Version == "" {
+		c.Version = "7.5.1"
+	}
+
+	m := Module{
+		config: c,
+		store: &store{
+			indices: map[string]map[string]*document{},
+		},
+	}
+	m.server = httptest.NewServer(m.Handler())
+
+	return m
+}
+
+func (m Module) URL() string {
+	return m.server.URL
+}
+
+func (m Module) Handler() http.Handler {
+	return http.HandlerFunc(m.serve)
+}
+
+func (m Module) Close() {
+	m.server.Close()
+}
+
+func (m Module) Put(index, id string, document interface{}) error {
+	source, err := encode(document)
+	if err != nil {
+		return err
+	}
+
+	_, _, err = m.store.put(index, id, source)
+	return err
+}
+
+func (m Module) Get(index, id string) (json.RawMessage, bool) {
+	m.store.mutex.RLock()
+	defer m.store.mutex.RUnlock()
+
+	doc, ok := m.store.indices[index][id]
+	if !ok {
+		return nil, false
+	}
+
+	return doc.source, true
+}
+
+func (m Module) Count(index string) int {
+	m.store.mutex.RLock()
+	defer m.store.mutex.RUnlock()
+
+	return len(m.store.indices[index])
+}
+
+func (m Module) Reset() {
+	m.store.mutex.Lock()
+	defer m.store.mutex.Unlock()
+
+	m.store.indices = map[string]map[string]*document{}
+}
+
+func (m Module) serve(w http.ResponseWriter, r *http.Request) {
+	var segments []string
+	for _, s := range strings.Split(strings.Trim(r.URL.Path, "/"), "/") {
+		if s != "" {
+			segments = append(segments, s)
+		}
+	}
+
+	// pre 7 paths carry a mapping type, /index/type/id and /index/type/_search
+	if len(segments) == 3 && !strings.HasPrefix(segments[1], "_") {
+		if strings.HasPrefix(segments[2], "_") {
+			segments = append(segments[:1], segments[2:]...)
+		} else {
+			segments[1] = "_doc"
+		}
+	}
+
+	switch {
+	case len(segments) == 0:
+		m.info(w, r)
+	case len(segments) == 1 && segments[0] == "_bulk":
+		m.bulk(w, r, "")
+	case len(segments) == 2 && segments[1] == "_bulk":
+		m.bulk(w, r, segments[0])
+	case len(segments) == 2 && segments[1] == "_search":
+		m.search(w, r, segments[0])
+	case len(segments) == 2 && segments[1] == "_count":
+		m.count(w, r, segments[0])
+	case len(segments) == 2 && segments[1] == "_delete_by_query":
+		m.deleteByQuery(w, r, segments[0])
+	case len(segments) == 3 && segments[1] == "_doc":
+		m.document(w, r, segments[0], segments[2])
+	default:
+		m.error(w, http.StatusBadRequest, "illegal_argument_exception", "no handler found for uri [%s] and method [%s]", r.URL.Path, r.Method)
+	}
+}
+
+func (m Module) info(w http.ResponseWriter, r *http.Request) {
+	m.write(w, http.StatusOK, map[string]interface{}{
+		"name":         "fake",
+		"cluster_name": "fake",
+		"cluster_uuid": "_na_",
+		"version": map[string]interface{}{
+			"number":         m.config.Version,
+			"build_flavor":   "default",
+			"build_type":     "fake",
+			"lucene_version": "8.3.0",
+		},
+		"tagline": "You Know, for Search",
+	})
+}
+
+func (m Module) search(w http.ResponseWriter, r *http.Request, index string) {
+	start := time.Now()
+
+	var request struct {
+		From  int64                  `json:"from"`
+		Size  *int64                 `json:"size"`
+		Query json.RawMessage        `json:"query"`
+		Sort  map[string]interface{} `json:"sort"`
+	}
+	if !m.decode(w, r, &request) {
+		return
+	}
+
+	size := int64(defaultSize)
+	if request.Size != nil {
+		size = *request.Size
+	}
+
+	order, err := parseSort(request.Sort)
+	if err != nil {
+		m.error(w, http.StatusBadRequest, "parsing_exception", "%s", err)
+		return
+	}
+
+	docs, ok := m.find(w, index, request.Query)
+	if !ok {
+		return
+	}
+	order.apply(docs)
+
+	total := len(docs)
+	if request.From < int64(len(docs)) {
+		docs = docs[request.From:]
+	} else {
+		docs = nil
+	}
+	if size < int64(len(docs)) {
+		docs = docs[:size]
+	}
+
+	var (
+		hits     = make([]map[string]interface{}, 0, len(docs))
+		maxScore interface{}
+	)
+	for _, doc := range docs {
+		hit := map[string]interface{}{
+			"_index":  index,
+			"_type":   "_doc",
+			"_id":     doc.id,
+			"_score":  1.0,
+			"_source": doc.source,
+		}
+		if len(order) > 0 {
+			hit["_score"] = nil
+			values := make([]interface{}, 0, len(order))
+			for _, f := range order {
+				values = append(values, f.value(doc))
+			}
+			hit["sort"] = values
+		} else {
+			maxScore = 1.0
+		}
+		hits = append(hits, hit)
+	}
+
+	m.write(w, http.StatusOK, map[string]interface{}{
+		"took":      took(start),
+		"timed_out": false,
+		"_shards":   shards,
+		"hits": map[string]interface{}{
+			"total":     map[string]interface{}{"value": total, "relation": "eq"},
+			"max_score": maxScore,
+			"hits":      hits,
+		},
+	})
+}
+
+func (m Module) count(w http.ResponseWriter, r *http.Request, index string) {
+	var request struct {
+		Query json.RawMessage `json:"query"`
+	}
+	if !m.decode(w, r, &request) {
+		return
+	}
+
+	docs, ok := m.find(w, index, request.Query)
+	if !ok {
+		return
+	}
+
+	m.write(w, http.StatusOK, map[string]interface{}{
+		"count":   len(docs),
+		"_shards": shards,
+	})
+}
+
+func (m Module) deleteByQuery(w http.ResponseWriter, r *http.Request, index string) {
+	start := time.Now()
+
+	var request struct {
+		Query json.RawMessage `json:"query"`
+	}
+	if !m.decode(w, r, &request) {
+		return
+	}
+	if len(request.Query) == 0 {
+		m.error(w, http.StatusBadRequest, "action_request_validation_exception", "Validation Failed: 1: query is missing;")
+		return
+	}
+
+	docs, ok := m.find(w, index, request.Query)
+	if !ok {
+		return
+	}
+
+	deleted := 0
+	for _, doc := range docs {
+		if _, ok := m.store.remove(index, doc.id); ok {
+			deleted++
+		}
+	}
+
+	m.write(w, http.StatusOK, map[string]interface{}{
+		"took":              took(start),
+		"timed_out":         false,
+		"total":             len(docs),
+		"deleted":           deleted,
+		"batches":           1,
+		"version_conflicts": len(docs) - deleted,
+		"noops":             0,
+		"failures":          []interface{}{},
+	})
+}
+
+func (m Module) document(w http.ResponseWriter, r *http.Request, index, id string) {
+	switch r.Method {
+	case http.MethodPut, http.MethodPost:
+		source, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			m.error(w, http.StatusBadRequest, "parse_exception", "%s", err)
+			return
+		}
+
+		doc, created, err := m.store.put(index, id, source)
+		if err != nil {
+			m.error(w, http.StatusBadRequest, "mapper_parsing_exception", "failed to parse: %s", err)
+			return
+		}
+
+		status, result := http.StatusOK, "updated"
+		if created {
+			status, result = http.StatusCreated, "created"
+		}
+
+		m.write(w, status, m.result(index, doc, result))
+	case http.MethodDelete:
+		doc, ok := m.store.remove(index, id)
+		if !ok {
+			m.write(w, http.StatusNotFound, m.result(index, &document{id: id, version: 1}, "not_found"))
+			return
+		}
+
+		m.write(w, http.StatusOK, m.result(index, doc, "deleted"))
+	default:
+		m.error(w, http.StatusMethodNotAllowed, "illegal_argument_exception", "incorrect HTTP method for uri [%s] and method [%s]", r.URL.Path, r.Method)
+	}
+}
+
+func (m Module) bulk(w http.ResponseWriter, r *http.Request, index string) {
+	start := time.Now()
+
+	var (
+		items   []map[string]interface{}
+		errored bool
+		scanner = bufio.NewScanner(r.Body)
+	)
+	scanner.Buffer(make([]byte, 64*1024), 100*1024*1024)
+
+	next := func() ([]byte, bool) {
+		for scanner.Scan() {
+			if line := bytes.TrimSpace(scanner.Bytes()); len(line) > 0 {
+				return append([]byte(nil), line...), true
+			}
+		}
+		return nil, false
+	}
+
+	for {
+		line, ok := next()
+		if !ok {
+			break
+		}
+
+		var action map[string]struct {
+			Index string `json:"_index"`
+			ID    string `json:"_id"`
+		}
+		if err := json.Unmarshal(line, &action); err != nil || len(action) != 1 {
+			m.error(w, http.StatusBadRequest, "illegal_argument_exception", "Malformed action/metadata line [%d], found [%s]", len(items)+1, line)
+			return
+		}
+
+		for name, meta := range action {
+			if meta.Index == "" {
+				meta.Index = index
+			}
+
+			var item map[string]interface{}
+
+			switch name {
+			case "index":
+				source, ok := next()
+				if !ok {
+					m.error(w, http.StatusBadRequest, "illegal_argument_exception", "The bulk request must be terminated by a newline [\\n]")
+					return
+				}
+
+				doc, created, err := m.store.put(meta.Index, meta.ID, source)
+				if err != nil {
+					errored = true
+					item = map[string]interface{}{
+						"_index": meta.Index,
+						"_id":    meta.ID,
+						"status": http.StatusBadRequest,
+						"error":  map[string]interface{}{"type": "mapper_parsing_exception", "reason": "failed to parse: " + err.Error()},
+					}
+					break
+				}
+
+				item = m.result(meta.Index, doc, "updated")
+				item["status"] = http.StatusOK
+				if created {
+					item["result"], item["status"] = "created", http.StatusCreated
+				}
+			case "delete":
+				doc, ok := m.store.remove(meta.Index, meta.ID)
+				if !ok {
+					item = m.result(meta.Index, &document{id: meta.ID, version: 1}, "not_found")
+					item["status"] = http.StatusNotFound
+					break
+				}
+
+				item = m.result(meta.Index, doc, "deleted")
+				item["status"] = http.StatusOK
+			default:
+				m.error(w, http.StatusBadRequest, "illegal_argument_exception", "Malformed action/metadata line [%d], expected one of [delete, index] but found [%s]", len(items)+1, name)
+				return
+			}
+
+			items = append(items, map[string]interface{}{name: item})
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		m.error(w, http.StatusBadRequest, "parse_exception", "%s", err)
+		return
+	}
+
+	m.write(w, http.StatusOK, map[string]interface{}{
+		"took":   took(start),
+		"errors": errored,
+		"items":  items,
+	})
+}
+
+func (m Module) find(w http.ResponseWriter, index string, raw json.RawMessage) ([]*document, bool) {
+	q, err := parseQuery(raw)
+	if err != nil {
+		m.error(w, http.StatusBadRequest, "parsing_exception", "%s", err)
+		return nil, false
+	}
+
+	docs, ok := m.store.find(index, q)
+	if !ok {
+		m.error(w, http.StatusNotFound, "index_not_found_exception", "no such index [%s]", index)
+		return nil, false
+	}
+
+	return docs, true
+}
+
+func (m Module) result(index string, doc *document, result string) map[string]interface{} {
+	return map[string]interface{}{
+		"_index":        index,
+		"_type":         "_doc",
+		"_id":           doc.id,
+		"_version":      doc.version,
+		"result":        result,
+		"_shards":       map[string]interface{}{"total": 1, "successful": 1, "failed": 0},
+		"_seq_no":       doc.seq,
+		"_primary_term": 1,
+	}
+}
+
+func (m Module) decode(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		m.error(w, http.StatusBadRequest, "parse_exception", "%s", err)
+		return false
+	}
+
+	if len(bytes.TrimSpace(body)) == 0 {
+		return true
+	}
+
+	if err := json.Unmarshal(body, v); err != nil {
+		m.error(w, http.StatusBadRequest, "parsing_exception", "%s", err)
+		return false
+	}
+
+	return true
+}
+
+func (m Module) write(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+	w.WriteHeader(status)
+
+	json.NewEncoder(w).Encode(body) // the client went away, nothing left to tell it
+}
+
+func (m Module) error(w http.ResponseWriter, status int, kind, format string, args ...interface{}) {
+	reason := fmt.Sprintf(format, args...)
+
+	m.write(w, status, map[string]interface{}{
+		"error": map[string]interface{}{
+			"root_cause": []interface{}{
+				map[string]interface{}{"type": kind, "reason": reason},
+			},
+			"type":   kind,
+			"reason": reason,
+		},
+		"status": status,
+	})
+}
+
+func (s *store) put(index, id string, source []byte) (*document, bool, error) {
+	var fields map[string]interface{}
+	if err := json.Unmarshal(source, &fields); err != nil {
+		return nil, false, err
+	}
+	if fields == nil {
+		return nil, false, fmt.Errorf("document is not an object")
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.seq++
+	if id == "" {
+		id = "fake-" + strconv.FormatInt(s.seq, 36)
+	}
+
+	docs, ok := s.indices[index]
+	if !ok {
+		docs = map[string]*document{}
+		s.indices[index] = docs
+	}
+
+	doc := &document{
+		id:      id,
+		source:  json.RawMessage(source),
+		fields:  fields,
+		version: 1,
+		seq:     s.seq,
+	}
+
+	previous, exists := docs[id]
+	if exists {
+		doc.version = previous.version + 1
+	}
+	docs[id] = doc
+
+	return doc, !exists, nil
+}
+
+func (s *store) remove(index, id string) (*document, bool) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	doc, ok := s.indices[index][id]
+	if !ok {
+		return nil, false
+	}
+	delete(s.indices[index], id)
+
+	s.seq++
+	return &document{id: id, version: doc.version + 1, seq: s.seq}, true
+}
+
+func (s *store) find(index string, q query) ([]*document, bool) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	indexed, ok := s.indices[index]
+	if !ok {
+		return nil, false
+	}
+
+	var docs []*document
+	for _, doc := range indexed {
+		if q.match(doc) {
+			docs = append(docs, doc)
+		}
+	}
+
+	sort.Slice(docs, func(i, j int) bool {
+		return docs[i].seq < docs[j].seq
+	})
+
+	return docs, true
+}
+
+func encode(document interface{}) ([]byte, error) {
+	switch d := document.(type) {
+	case json.RawMessage:
+		return d, nil
+	case []byte:
+		return d, nil
+	case string:
+		return []byte(d), nil
+	}
+
+	return json.Marshal(document)
+}
+
+func took(start time.Time) int64 {
+	return int64(time.Since(start) / time.Millisecond)
+}
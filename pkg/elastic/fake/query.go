@@ -0,0 +1,522 @@
+package fake
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+type (
+	query interface {
+		match(doc *document) bool
+	}
+
+	matchAll struct{}
+
+	allOf []query
+
+	anyOf []query
+
+	not struct {
+		query query
+	}
+
+	term struct {
+		field string // any field when empty
+		value string
+	}
+
+	dateRange struct {
+		field    string
+		from, to time.Time // to is exclusive, zero leaves the range open
+	}
+
+	sortField struct {
+		field string
+		desc  bool
+	}
+
+	sortOrder []sortField
+
+	queryString struct {
+		tokens []string
+		pos    int
+	}
+)
+
+var dateLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+}
+
+func parseQuery(raw json.RawMessage) (query, error) {
+	if len(raw) == 0 || string(raw) == "null" {
+		return matchAll{}, nil
+	}
+
+	var clauses map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &clauses); err != nil {
+		return nil, fmt.Errorf("[query] malformed query, expected an object: %s", err)
+	}
+
+	var q allOf
+
+	for name, body := range clauses {
+		var (
+			clause query
+			err    error
+		)
+
+		switch name {
+		case "match_all":
+			clause = matchAll{}
+		case "bool":
+			clause, err = parseBool(body)
+		case "query_string":
+			var v struct {
+				Query string `json:"query"`
+			}
+			if err = json.Unmarshal(body, &v); err == nil {
+				clause, err = parseQueryString(v.Query)
+			}
+		case "range":
+			clause, err = parseRange(body)
+		default:
+			err = fmt.Errorf("unknown query [%s]", name)
+		}
+
+		if err != nil {
+			return nil, err
+		}
+		q = append(q, clause)
+	}
+
+	return q, nil
+}
+
+func parseBool(raw json.RawMessage) (query, error) {
+	var v map[string][]json.RawMessage
+	if err := json.Unmarshal(raw, &v); err != nil {
+		return nil, fmt.Errorf("[bool] %s", err)
+	}
+
+	var must, should []query
+
+	for name, list := range v {
+		for _, item := range list {
+			q, err := parseQuery(item)
+			if err != nil {
+				return nil, err
+			}
+
+			switch name {
+			case "must", "filter":
+				must = append(must, q)
+			case "should":
+				should = append(should, q)
+			default:
+				return nil, fmt.Errorf("[bool] query does not support [%s]", name)
+			}
+		}
+	}
+
+	if len(must) > 0 || len(should) == 0 {
+		return allOf(must), nil
+	}
+
+	return anyOf(should), nil
+}
+
+func parseRange(raw json.RawMessage) (query, error) {
+	var v map[string]map[string]string
+	if err := json.Unmarshal(raw, &v); err != nil || len(v) != 1 {
+		return nil, fmt.Errorf("[range] query malformed, expected a single date field")
+	}
+
+	for field, options := range v {
+		location := time.UTC
+		if zone := options["time_zone"]; zone != "" {
+			t, err := time.Parse("-07:00", zone)
+			if err != nil {
+				return nil, fmt.Errorf("[range] unknown time_zone [%s]", zone)
+			}
+			location = t.Location()
+		}
+
+		r := dateRange{field: field}
+
+		for name, value := range options {
+			switch name {
+			case "gte", "lte":
+				day, err := time.ParseInLocation("2006-01-02", value, location)
+				if err != nil {
+					return nil, fmt.Errorf("[range] failed to parse date [%s]", value)
+				}
+
+				if name == "gte" {
+					r.from = day
+				} else {
+					r.to = day.AddDate(0, 0, 1)
+				}
+			case "format", "time_zone":
+			default:
+				return nil, fmt.Errorf("[range] query does not support [%s]", name)
+			}
+		}
+
+		return r, nil
+	}
+
+	return matchAll{}, nil
+}
+
+func parseQueryString(s string) (query, error) {
+	p := &queryString{}
+
+	for i := 0; i < len(s); {
+		r := rune(s[i])
+
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(' || r == ')':
+			p.tokens = append(p.tokens, string(r))
+			i++
+		default:
+			start := i
+			quoted := false
+			for i < len(s) && (quoted || !(unicode.IsSpace(rune(s[i])) || s[i] == '(' || s[i] == ')')) {
+				if s[i] == '"' {
+					quoted = !quoted
+				}
+				i++
+			}
+			if quoted {
+				return nil, fmt.Errorf("[query_string] unterminated quote in [%s]", s)
+			}
+			p.tokens = append(p.tokens, s[start:i])
+		}
+	}
+
+	if len(p.tokens) == 0 {
+		return matchAll{}, nil
+	}
+
+	q, err := p.or()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.tokens) {
+		return nil, fmt.Errorf("[query_string] unexpected [%s] in [%s]", p.tokens[p.pos], s)
+	}
+
+	return q, nil
+}
+
+func (p *queryString) or() (query, error) {
+	var any anyOf
+
+	for p.pos < len(p.tokens) && p.tokens[p.pos] != ")" {
+		if p.tokens[p.pos] == "OR" {
+			p.pos++
+			continue
+		}
+
+		q, err := p.and()
+		if err != nil {
+			return nil, err
+		}
+		any = append(any, q)
+	}
+
+	if len(any) == 1 {
+		return any[0], nil
+	}
+
+	return any, nil
+}
+
+func (p *queryString) and() (query, error) {
+	q, err := p.unary()
+	if err != nil {
+		return nil, err
+	}
+
+	all := allOf{q}
+
+	for p.pos < len(p.tokens) && p.tokens[p.pos] == "AND" {
+		p.pos++
+
+		q, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, q)
+	}
+
+	if len(all) == 1 {
+		return all[0], nil
+	}
+
+	return all, nil
+}
+
+func (p *queryString) unary() (query, error) {
+	if p.pos == len(p.tokens) {
+		return nil, fmt.Errorf("[query_string] unexpected end of query")
+	}
+
+	token := p.tokens[p.pos]
+	p.pos++
+
+	switch token {
+	case "NOT":
+		q, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		return not{query: q}, nil
+	case "(":
+		q, err := p.or()
+		if err != nil {
+			return nil, err
+		}
+		if p.pos == len(p.tokens) || p.tokens[p.pos] != ")" {
+			return nil, fmt.Errorf("[query_string] missing )")
+		}
+		p.pos++
+		return q, nil
+	case ")", "AND", "OR":
+		return nil, fmt.Errorf("[query_string] unexpected [%s]", token)
+	case "*":
+		return matchAll{}, nil
+	}
+
+	t := term{value: token}
+	if i := strings.Index(token, ":"); i >= 0 && !strings.HasPrefix(token, `"`) {
+		t.field, t.value = token[:i], token[i+1:]
+	}
+
+	t.value = strings.Trim(t.value, `"`)
+	if t.value == "" {
+		return nil, fmt.Errorf("[query_string] missing value for [%s]", t.field)
+	}
+
+	return t, nil
+}
+
+func (matchAll) match(*document) bool {
+	return true
+}
+
+func (a allOf) match(doc *document) bool {
+	for _, q := range a {
+		if !q.match(doc) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (a anyOf) match(doc *document) bool {
+	for _, q := range a {
+		if q.match(doc) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (n not) match(doc *document) bool {
+	return !n.query.match(doc)
+}
+
+func (t term) match(doc *document) bool {
+	var values []interface{}
+
+	if t.field == "" {
+		for _, value := range doc.fields {
+			values = append(values, flatten(value)...)
+		}
+	} else {
+		values = lookup(doc.fields, t.field)
+	}
+
+	for _, value := range values {
+		switch v := value.(type) {
+		case string:
+			if strings.EqualFold(v, t.value) {
+				return true
+			}
+		case float64:
+			if f, err := strconv.ParseFloat(t.value, 64); err == nil && f == v {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func (r dateRange) match(doc *document) bool {
+	for _, value := range lookup(doc.fields, r.field) {
+		s, _ := value.(string)
+
+		t, ok := parseDate(s)
+		if !ok {
+			continue
+		}
+
+		if (r.from.IsZero() || !t.Before(r.from)) && (r.to.IsZero() || t.Before(r.to)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func parseDate(s string) (time.Time, bool) {
+	for _, layout := range dateLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, true
+		}
+	}
+
+	return time.Time{}, false
+}
+
+func lookup(fields interface{}, field string) []interface{} {
+	switch v := fields.(type) {
+	case map[string]interface{}:
+		name, rest := field, ""
+		if i := strings.Index(field, "."); i >= 0 {
+			name, rest = field[:i], field[i+1:]
+		}
+
+		value, ok := v[name]
+		if !ok {
+			return nil
+		}
+		if rest == "" {
+			return flatten(value)
+		}
+		return lookup(value, rest)
+	case []interface{}:
+		var values []interface{}
+		for _, item := range v {
+			values = append(values, lookup(item, field)...)
+		}
+		return values
+	}
+
+	return nil
+}
+
+func flatten(value interface{}) []interface{} {
+	list, ok := value.([]interface{})
+	if !ok {
+		return []interface{}{value}
+	}
+
+	var values []interface{}
+	for _, item := range list {
+		values = append(values, flatten(item)...)
+	}
+
+	return values
+}
+
+func parseSort(raw map[string]interface{}) (sortOrder, error) {
+	fields := make([]string, 0, len(raw))
+	for field := range raw {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	var order sortOrder
+
+	for _, field := range fields {
+		direction := raw[field]
+		if options, ok := direction.(map[string]interface{}); ok {
+			direction = options["order"]
+		}
+
+		switch direction {
+		case "asc", nil:
+			order = append(order, sortField{field: field})
+		case "desc":
+			order = append(order, sortField{field: field, desc: true})
+		default:
+			return nil, fmt.Errorf("[sort] unknown order [%v] for [%s]", direction, field)
+		}
+	}
+
+	return order, nil
+}
+
+func (o sortOrder) apply(docs []*document) {
+	sort.SliceStable(docs, func(i, j int) bool {
+		for _, f := range o {
+			a, b := f.value(docs[i]), f.value(docs[j])
+
+			switch {
+			case a == nil && b == nil:
+				continue
+			case a == nil:
+				return false
+			case b == nil:
+				return true
+			}
+
+			c := compare(a, b)
+			if c == 0 {
+				continue
+			}
+			return (c < 0) != f.desc
+		}
+		return false
+	})
+}
+
+func (f sortField) value(doc *document) interface{} {
+	values := lookup(doc.fields, f.field)
+	if len(values) == 0 {
+		return nil
+	}
+
+	if s, ok := values[0].(string); ok {
+		if t, ok := parseDate(s); ok {
+			return float64(t.UnixNano() / int64(time.Millisecond))
+		}
+	}
+
+	return values[0]
+}
+
+func compare(a, b interface{}) int {
+	fa, aNumber := a.(float64)
+	fb, bNumber := b.(float64)
+
+	switch {
+	case aNumber && bNumber && fa < fb:
+		return -1
+	case aNumber && bNumber && fa > fb:
+		return 1
+	case aNumber && bNumber:
+		return 0
+	case aNumber:
+		return -1
+	case bNumber:
+		return 1
+	}
+
+	return strings.Compare(fmt.Sprint(a), fmt.Sprint(b))
+}
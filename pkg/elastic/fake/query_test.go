@@ -0,0 +1,88 @@
+package fake
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestParseQueryString(t *testing.T) {
+	doc := &document{id: "1"}
+	if err := json.Unmarshal([]byte(`{
+		"order_id": 69696969,
+		"source": "marketplace",
+		"platform": "android",
+		"amount": 25000,
+		"create_time": "2020-05-14T20:00:00+07:00",
+		"promo_detail": {"promo_code": "CASHBACK10", "coverage": [{"type": "shipping"}, {"type": "cashback"}]}
+	}`), &doc.fields); err != nil {
+		t.Fatal(err)
+	}
+
+	for query, want := range map[string]bool{
+		"":                                    true,
+		"*":                                   true,
+		"source:marketplace":                  true,
+		"source:Marketplace":                  true,
+		"source:digital":                      false,
+		"marketplace":                         true,
+		"source:marketplace AND platform:ios": false,
+		"source:marketplace platform:ios":     true,
+		"NOT source:marketplace":              false,
+		"(source:digital OR platform:android) AND *": true,
+		`promo_detail.promo_code:"CASHBACK10"`:       true,
+		"promo_detail.coverage.type:cashback":        true,
+		"order_id:69696969 OR order_id:96969696":     true,
+		"order_id:96969696":                          false,
+	} {
+		q, err := parseQueryString(query)
+		if err != nil {
+			t.Errorf("%q: %v", query, err)
+			continue
+		}
+
+		if got := q.match(doc); got != want {
+			t.Errorf("%q matched %v, want %v", query, got, want)
+		}
+	}
+}
+
+func TestParseQueryStringErrors(t *testing.T) {
+	for _, query := range []string{
+		"(source:marketplace",
+		"source:",
+		`source:"marketplace`,
+		"source:marketplace AND",
+		"source:marketplace)",
+	} {
+		if _, err := parseQueryString(query); err == nil {
+			t.Errorf("%q parsed, want an error", query)
+		}
+	}
+}
+
+func TestParseQuery(t *testing.T) {
+	doc := &document{id: "1"}
+	if err := json.Unmarshal([]byte(`{"source": "marketplace", "create_time": "2020-05-14T20:00:00Z"}`), &doc.fields); err != nil {
+		t.Fatal(err)
+	}
+
+	for query, want := range map[string]bool{
+		`{}`: true,
+		`{"bool": {"must": [{"query_string": {"query": "source:marketplace"}}]}}`:                                   true,
+		`{"bool": {"should": [{"query_string": {"query": "source:digital"}}]}}`:                                     false,
+		`{"range": {"create_time": {"gte": "2020-05-15", "lte": "2020-05-15", "time_zone": "+07:00"}}}`:             true,
+		`{"range": {"create_time": {"gte": "2020-05-14", "lte": "2020-05-14", "time_zone": "+07:00"}}}`:             false,
+		`{"range": {"create_time": {"gte": "2020-05-14", "lte": "2020-05-14", "format": "yyyy-MM-dd"}}}`:            true,
+		`{"bool": {"must": [{"range": {"create_time": {"gte": "2020-05-01"}}}, {"query_string": {"query": "*"}}]}}`: true,
+	} {
+		q, err := parseQuery(json.RawMessage(query))
+		if err != nil {
+			t.Errorf("%s: %v", query, err)
+			continue
+		}
+
+		if got := q.match(doc); got != want {
+			t.Errorf("%s matched %v, want %v", query, got, want)
+		}
+	}
+}
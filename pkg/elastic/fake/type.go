@@ -0,0 +1,47 @@
+package fake
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+)
+
+type (
+	Method interface {
+		URL() string
+		Handler() http.Handler
+		Close()
+
+		Put(index, id string, document interface{}) error
+		Get(index, id string) (json.RawMessage, bool)
+		Count(index string) int
+		Reset()
+	}
+)
+
+type (
+	Config struct {
+		Version string // reported by the root endpoint, defaults to 7.5.1
+	}
+
+	Module struct {
+		config Config
+		server *httptest.Server
+		store  *store
+	}
+
+	store struct {
+		mutex   sync.RWMutex
+		indices map[string]map[string]*document
+		seq     int64
+	}
+
+	document struct {
+		id      string
+		source  json.RawMessage
+		fields  map[string]interface{}
+		version int64
+		seq     int64
+	}
+)
@@ -0,0 +1,193 @@
+package nethttp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/elastic-fray/pkg/monitor"
+	"github.com/elastic-fray/pkg/tracing"
+
+	"github.com/tokopedia/tdk/go/log"
+
+	"github.com/tokopedia/sauron/src/elastic"
+)
+
+var (
+	once sync.Once
+	m    Module
+)
+
+func New(c Config) Method {
+	once.Do(func() {
+		m = Module{
+			config: c.Config,
+			client: &http.Client{
+				Transport: c.Transport,
+			},
+		}
+	})
+
+	return m
+}
+
+func (m Module) ProcessSearch(ctx context.Context, so *elastic.SearchOption) error {
+	size := so.Size
+	if size <= 0 {
+		size = elastic.MAX_ELASTIC_SIZE
+	}
+
+	esq := elastic.ElasticSearchQuery{
+		Size:  size,
+		Query: so.Input,
+		Sort:  so.Sort,
+	}
+
+	return m.do(ctx, http.MethodPost, m.index(so.Environment, so.Index)+"/_search", esq, so.Output)
+}
+
+func (m Module) ProcessCount(ctx context.Context, so *elastic.SearchOption) (int, error) {
+	var resp struct {
+		Count int `json:"count"`
+	}
+
+	err := m.do(ctx, http.MethodPost, m.index(so.Environment, so.Index)+"/_count", elastic.ElasticSearchQuery{
+		Query: so.Input,
+	}, &resp)
+
+	return resp.Count, err
+}
+
+func (m Module) ProcessInsert(ctx context.Context, so *elastic.InsertOption) error {
+	return m.do(ctx, http.MethodPut, m.document(so)+"?refresh=true", so.Data, nil)
+}
+
+func (m Module) ProcessUpdate(ctx context.Context, so *elastic.InsertOption) error {
+	return m.do(ctx, http.MethodPut, m.document(so)+"?refresh=true", so.Data, nil)
+}
+
+func (m Module) ProcessDelete(ctx context.Context, id string, so *elastic.DeleteOption) (string, error) {
+	var resp struct {
+		Result string `json:"result"`
+	}
+
+	err := m.do(ctx, http.MethodDelete, m.index(so.Environment, so.Index)+"/_doc/"+url.PathEscape(id), nil, &resp)
+
+	return resp.Result, err
+}
+
+func (m Module) ProcessBulk(ctx context.Context, body io.Reader) (bool, error) {
+	var resp struct {
+		Errors bool `json:"errors"`
+	}
+
+	err := m.send(ctx, http.MethodPost, "/_bulk", "application/x-ndjson", body, &resp)
+
+	return resp.Errors, err
+}
+
+func (m Module) index(environment bool, index string) string {
+	if !environment {
+		return "/" + url.PathEscape(index)
+	}
+
+	prefix := m.config.Server.Environment
+	if prefix == "development" {
+		prefix = "staging"
+	}
+
+	return "/" + url.PathEscape(prefix+"-"+index)
+}
+
+func (m Module) document(so *elastic.InsertOption) string {
+	return m.index(so.Environment, so.Index) + "/_doc/" + url.PathEscape(so.ID)
+}
+
+func (m Module) do(ctx context.Context, method, path string, input, output interface{}) error {
+	var body io.Reader
+
+	if input != nil {
+		data, err := json.Marshal(input)
+		if err != nil {
+			log.Error(err)
+			return err
+		}
+		body = bytes.NewReader(data)
+	}
+
+	return m.send(ctx, method, path, "application/json", body, output)
+}
+
+func (m Module) send(ctx context.Context, method, path, contentType string, body io.Reader, output interface{}) (err error) {
+	ctx, span := tracing.StartRequest(ctx, "nethttp."+method, attribute.String("http.method", method), attribute.String("http.target", path))
+	defer func() { tracing.End(ctx, span, err) }()
+
+	target, err := url.Parse(strings.TrimRight(m.config.ElasticSearch.URL, "/") + path)
+	if err != nil {
+		log.Error(err)
+		return err
+	}
+
+	req, err := http.NewRequest(method, target.String(), body)
+	if err != nil {
+		log.Error(err)
+		return err
+	}
+	req = req.WithContext(ctx)
+
+	if body != nil {
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	if user := target.User; user != nil {
+		password, _ := user.Password()
+		req.SetBasicAuth(user.Username(), password)
+		req.URL.User = nil
+	}
+
+	resp, err := m.client.Do(req)
+	if err != nil {
+		log.Error(err)
+		return err
+	}
+	defer resp.Body.Close()
+	monitor.SetStatus(ctx, resp.StatusCode)
+
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		log.Error(err)
+		return err
+	}
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		var e Error
+		if json.Unmarshal(data, &e) != nil || e.Error.Type == "" {
+			err = fmt.Errorf("[%s] %s", resp.Status, bytes.TrimSpace(data))
+		} else {
+			err = fmt.Errorf("[%s] %s: %s", resp.Status, e.Error.Type, e.Error.Reason)
+		}
+
+		log.Error(err)
+		return err
+	}
+
+	if output == nil {
+		return nil
+	}
+
+	if err := json.Unmarshal(data, output); err != nil {
+		log.Error(err)
+		return err
+	}
+
+	return nil
+}
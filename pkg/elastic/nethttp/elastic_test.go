@@ -0,0 +1,177 @@
+package nethttp
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/elastic-fray/entity/promo/marketplace"
+	"github.com/elastic-fray/pkg/elastic/fake"
+	"github.com/elastic-fray/pkg/utils"
+
+	"github.com/tokopedia/sauron/src/elastic"
+)
+
+const testIndex = "promo-order-usage"
+
+var (
+	testModule Method
+	testServer fake.Method
+)
+
+type searchResponse struct {
+	Hits struct {
+		Hits []struct {
+			ID     string            `json:"_id"`
+			Source marketplace.Promo `json:"_source"`
+		} `json:"hits"`
+	} `json:"hits"`
+}
+
+func TestMain(m *testing.M) {
+	testServer = fake.New(fake.Config{})
+
+	testModule = New(Config{
+		Config: utils.Config{
+			Server: utils.ServerConfig{
+				Environment: "development",
+			},
+			ElasticSearch: utils.ElasticSearchConfig{
+				URL: testServer.URL(),
+			},
+		},
+	})
+
+	code := m.Run()
+	testServer.Close()
+	os.Exit(code)
+}
+
+func queryString(query string) elastic.Query {
+	return elastic.Query{
+		Bool: &elastic.Bool{
+			Must: []elastic.Must{
+				{QueryString: map[string]interface{}{"query": query}},
+			},
+		},
+	}
+}
+
+func TestDocumentLifecycle(t *testing.T) {
+	ctx := context.Background()
+	testServer.Reset()
+
+	promo := marketplace.Promo{
+		OrderID:    69696969,
+		Source:     "marketplace",
+		Platform:   "android",
+		CreateTime: time.Date(2020, 5, 14, 20, 0, 0, 0, time.UTC),
+	}
+	insert := &elastic.InsertOption{
+		Environment: true,
+		Index:       testIndex,
+		ID:          "69696969",
+		Data:        promo,
+	}
+
+	if err := testModule.ProcessInsert(ctx, insert); err != nil {
+		t.Fatal(err)
+	}
+
+	// development writes to the staging indices
+	if _, ok := testServer.Get("staging-"+testIndex, "69696969"); !ok {
+		t.Fatal("inserted document not found in staging-" + testIndex)
+	}
+
+	promo.Platform = "ios"
+	insert.Data = promo
+	if err := testModule.ProcessUpdate(ctx, insert); err != nil {
+		t.Fatal(err)
+	}
+
+	var resp searchResponse
+	if err := testModule.ProcessSearch(ctx, &elastic.SearchOption{
+		Environment: true,
+		Index:       testIndex,
+		Input:       queryString("platform:ios"),
+		Output:      &resp,
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(resp.Hits.Hits) != 1 || resp.Hits.Hits[0].Source.OrderID != promo.OrderID {
+		t.Fatalf("search = %+v, want order %d", resp.Hits.Hits, promo.OrderID)
+	}
+
+	count, err := testModule.ProcessCount(ctx, &elastic.SearchOption{
+		Environment: true,
+		Index:       testIndex,
+		Input:       queryString("platform:android"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 0 {
+		t.Errorf("count of the old platform = %d, want 0", count)
+	}
+
+	remove := &elastic.DeleteOption{
+		Environment: true,
+		Index:       testIndex,
+	}
+
+	result, err := testModule.ProcessDelete(ctx, "69696969", remove)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result != "deleted" {
+		t.Errorf("delete result = %q, want deleted", result)
+	}
+
+	_, err = testModule.ProcessDelete(ctx, "69696969", remove)
+	if err == nil || !strings.Contains(err.Error(), "404") {
+		t.Errorf("deleting a missing document: err = %v, want a 404", err)
+	}
+}
+
+func TestProcessSearchMissingIndex(t *testing.T) {
+	testServer.Reset()
+
+	err := testModule.ProcessSearch(context.Background(), &elastic.SearchOption{
+		Index:  "missing",
+		Input:  queryString("*"),
+		Output: &searchResponse{},
+	})
+	if err == nil || !strings.Contains(err.Error(), "index_not_found_exception") {
+		t.Errorf("err = %v, want index_not_found_exception", err)
+	}
+}
+
+func TestProcessBulk(t *testing.T) {
+	testServer.Reset()
+
+	var body strings.Builder
+	for i := 1; i <= 3; i++ {
+		fmt.Fprintf(&body, `{"index":{"_index":"staging-%s","_type":"order","_id":"%d"}}`+"\n", testIndex, i)
+		fmt.Fprintf(&body, `{"order_id":%d,"source":"marketplace"}`+"\n", i)
+	}
+	fmt.Fprintf(&body, `{"index":{"_index":"staging-%s","_id":"4"}}`+"\n", testIndex)
+	fmt.Fprintf(&body, `"marketplace"`+"\n")
+
+	failed, err := testModule.ProcessBulk(context.Background(), strings.NewReader(body.String()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// a document that is not an object is a failed item, not a failed request
+	if !failed {
+		t.Error("bulk reported no failed item")
+	}
+
+	if n := testServer.Count("staging-" + testIndex); n != 3 {
+		t.Errorf("documents after bulk = %d, want 3", n)
+	}
+}
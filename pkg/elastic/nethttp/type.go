@@ -0,0 +1,42 @@
+package nethttp
+
+import (
+	"context"
+	"io"
+	"net/http"
+
+	"github.com/elastic-fray/pkg/utils"
+
+	"github.com/tokopedia/sauron/src/elastic"
+)
+
+type (
+	Method interface {
+		ProcessSearch(ctx context.Context, so *elastic.SearchOption) error
+		ProcessCount(ctx context.Context, so *elastic.SearchOption) (int, error)
+		ProcessInsert(ctx context.Context, so *elastic.InsertOption) error
+		ProcessUpdate(ctx context.Context, so *elastic.InsertOption) error
+		ProcessDelete(ctx context.Context, id string, so *elastic.DeleteOption) (string, error)
+		ProcessBulk(ctx context.Context, body io.Reader) (bool, error)
+	}
+)
+
+type (
+	Config struct {
+		Config    utils.Config
+		Transport http.RoundTripper // http.DefaultTransport when nil
+	}
+
+	Module struct {
+		config utils.Config
+		client *http.Client
+	}
+
+	Error struct {
+		Status int `json:"status"`
+		Error  struct {
+			Type   string `json:"type"`
+			Reason string `json:"reason"`
+		} `json:"error"`
+	}
+)
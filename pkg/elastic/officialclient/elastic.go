@@ -12,6 +12,9 @@ import (
 	"github.com/elastic/go-elasticsearch/v7"
 	"github.com/elastic/go-elasticsearch/v7/esapi"
 
+	"github.com/elastic-fray/pkg/monitor"
+	"github.com/elastic-fray/pkg/tracing"
+
 	"github.com/tokopedia/tdk/go/log"
 
 	"github.com/tokopedia/sauron/src/elastic"
@@ -45,11 +48,21 @@ func New(c Config) (Method, error) {
 	return m, err
 }
 
-func (m Module) GetInfo(ctx context.Context, o ...func(*esapi.InfoRequest)) (*esapi.Response, error) {
-	return m.elastic.Info()
+func (m Module) GetInfo(ctx context.Context, o ...func(*esapi.InfoRequest)) (res *esapi.Response, err error) {
+	ctx, span := tracing.StartRequest(ctx, "officialclient.Info")
+	defer func() { tracing.End(ctx, span, err) }()
+
+	res, err = m.elastic.Info(
+		m.elastic.Info.WithContext(ctx),
+	)
+	if err == nil {
+		monitor.SetStatus(ctx, res.StatusCode)
+	}
+
+	return res, err
 }
 
-func (m Module) ProcessSearch(ctx context.Context, so *elastic.SearchOption, o ...func(*esapi.SearchRequest)) error {
+func (m Module) ProcessSearch(ctx context.Context, so *elastic.SearchOption, o ...func(*esapi.SearchRequest)) (err error) {
 	var (
 		buffer bytes.Buffer
 		size   int64
@@ -85,8 +98,11 @@ func (m Module) ProcessSearch(ctx context.Context, so *elastic.SearchOption, o .
 		return err
 	}
 
+	ctx, span := tracing.StartRequest(ctx, "officialclient.Search", tracing.AttributeIndex.String(so.Index))
+	defer func() { tracing.End(ctx, span, err) }()
+
 	resp, err := m.elastic.Search(
-		// m.elastic.Search.WithContext(ctx),
+		m.elastic.Search.WithContext(ctx),
 		m.elastic.Search.WithIndex(so.Index),
 		m.elastic.Search.WithBody(&buffer),
 		// m.elastic.Search.WithTrackTotalHits(true),
@@ -97,6 +113,7 @@ func (m Module) ProcessSearch(ctx context.Context, so *elastic.SearchOption, o .
 		return err
 	}
 	defer resp.Body.Close()
+	monitor.SetStatus(ctx, resp.StatusCode)
 
 	if resp.IsError() {
 		var e map[string]interface{}
@@ -132,7 +149,7 @@ func (m Module) ProcessSearch(ctx context.Context, so *elastic.SearchOption, o .
 	return err
 }
 
-func (m Module) ProcessCount(ctx context.Context, so *elastic.SearchOption, o ...func(*esapi.CountRequest)) (int, error) {
+func (m Module) ProcessCount(ctx context.Context, so *elastic.SearchOption, o ...func(*esapi.CountRequest)) (count int, err error) {
 	var (
 		buffer bytes.Buffer
 		result map[string]interface{}
@@ -155,8 +172,11 @@ func (m Module) ProcessCount(ctx context.Context, so *elastic.SearchOption, o ..
 		return 0, err
 	}
 
+	ctx, span := tracing.StartRequest(ctx, "officialclient.Count", tracing.AttributeIndex.String(so.Index))
+	defer func() { tracing.End(ctx, span, err) }()
+
 	resp, err := m.elastic.Count(
-		// m.elastic.Count.WithContext(ctx),
+		m.elastic.Count.WithContext(ctx),
 		m.elastic.Count.WithIndex(so.Index),
 		m.elastic.Count.WithBody(&buffer),
 		// m.elastic.Count.WithPretty(),
@@ -166,6 +186,7 @@ func (m Module) ProcessCount(ctx context.Context, so *elastic.SearchOption, o ..
 		return 0, err
 	}
 	defer resp.Body.Close()
+	monitor.SetStatus(ctx, resp.StatusCode)
 
 	if resp.IsError() {
 		var e map[string]interface{}
@@ -187,7 +208,7 @@ func (m Module) ProcessCount(ctx context.Context, so *elastic.SearchOption, o ..
 	return int(result["count"].(float64)), err
 }
 
-func (m Module) ProcessInsert(ctx context.Context, so *elastic.InsertOption) error {
+func (m Module) ProcessInsert(ctx context.Context, so *elastic.InsertOption) (err error) {
 	if so.Environment == true {
 		if m.config.Server.Environment == "development" {
 			m.config.Server.Environment = "staging"
@@ -209,14 +230,19 @@ func (m Module) ProcessInsert(ctx context.Context, so *elastic.InsertOption) err
 		Refresh:    "true",
 	}
 
+	ctx, span := tracing.StartRequest(ctx, "officialclient.Index", tracing.AttributeIndex.String(so.Index), tracing.AttributeDocumentID.String(so.ID))
+	defer func() { tracing.End(ctx, span, err) }()
+
 	res, err := req.Do(ctx, m.elastic)
 	if err != nil {
 		log.Error(err)
+		return err
 	}
 	defer res.Body.Close()
+	monitor.SetStatus(ctx, res.StatusCode)
 
 	if res.IsError() {
-		log.Errorf("[%s] Error indexing document ID=%d", res.Status(), so.ID)
+		log.Errorf("[%s] Error indexing document ID=%s", res.Status(), so.ID)
 
 		return errors.New("Error")
 	} else {
@@ -232,7 +258,7 @@ func (m Module) ProcessInsert(ctx context.Context, so *elastic.InsertOption) err
 	return err
 }
 
-func (m Module) ProcessUpdate(ctx context.Context, so *elastic.InsertOption) error {
+func (m Module) ProcessUpdate(ctx context.Context, so *elastic.InsertOption) (err error) {
 	if so.Environment == true {
 		if m.config.Server.Environment == "development" {
 			m.config.Server.Environment = "staging"
@@ -254,14 +280,19 @@ func (m Module) ProcessUpdate(ctx context.Context, so *elastic.InsertOption) err
 		Refresh:    "true",
 	}
 
+	ctx, span := tracing.StartRequest(ctx, "officialclient.Index", tracing.AttributeIndex.String(so.Index), tracing.AttributeDocumentID.String(so.ID))
+	defer func() { tracing.End(ctx, span, err) }()
+
 	res, err := req.Do(ctx, m.elastic)
 	if err != nil {
 		log.Error(err)
+		return err
 	}
 	defer res.Body.Close()
+	monitor.SetStatus(ctx, res.StatusCode)
 
 	if res.IsError() {
-		log.Errorf("[%s] Error indexing document ID=%d", res.Status(), so.ID)
+		log.Errorf("[%s] Error indexing document ID=%s", res.Status(), so.ID)
 
 		return errors.New("Error")
 	} else {
@@ -277,7 +308,7 @@ func (m Module) ProcessUpdate(ctx context.Context, so *elastic.InsertOption) err
 	return err
 }
 
-func (m Module) ProcessDelete(ctx context.Context, id string, so *elastic.DeleteOption, o ...func(*esapi.DeleteRequest)) (string, error) {
+func (m Module) ProcessDelete(ctx context.Context, id string, so *elastic.DeleteOption, o ...func(*esapi.DeleteRequest)) (outcome string, err error) {
 	var result map[string]interface{}
 
 	if so.Environment == true {
@@ -288,15 +319,20 @@ func (m Module) ProcessDelete(ctx context.Context, id string, so *elastic.Delete
 		so.Index = m.config.Server.Environment + "-" + so.Index
 	}
 
+	ctx, span := tracing.StartRequest(ctx, "officialclient.Delete", tracing.AttributeIndex.String(so.Index), tracing.AttributeDocumentID.String(id))
+	defer func() { tracing.End(ctx, span, err) }()
+
 	resp, err := m.elastic.Delete(
 		so.Index,
 		id,
+		m.elastic.Delete.WithContext(ctx),
 	)
 	if err != nil {
 		log.Error(err)
 		return "", err
 	}
 	defer resp.Body.Close()
+	monitor.SetStatus(ctx, resp.StatusCode)
 
 	if resp.IsError() {
 		var e map[string]interface{}
@@ -318,15 +354,20 @@ func (m Module) ProcessDelete(ctx context.Context, id string, so *elastic.Delete
 	return result["result"].(string), err
 }
 
-func (m Module) ProcessBulk(ctx context.Context, body io.Reader, o ...func(*esapi.BulkRequest)) error {
+func (m Module) ProcessBulk(ctx context.Context, body io.Reader, o ...func(*esapi.BulkRequest)) (err error) {
+	ctx, span := tracing.StartRequest(ctx, "officialclient.Bulk")
+	defer func() { tracing.End(ctx, span, err) }()
+
 	resp, err := m.elastic.Bulk(
 		body,
+		m.elastic.Bulk.WithContext(ctx),
 	)
 	if err != nil {
 		log.Error(err)
 		return err
 	}
 	defer resp.Body.Close()
+	monitor.SetStatus(ctx, resp.StatusCode)
 
 	return err
 }
@@ -0,0 +1,241 @@
+package officialclient
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/elastic-fray/entity/promo/marketplace"
+	"github.com/elastic-fray/pkg/elastic/fake"
+	"github.com/elastic-fray/pkg/utils"
+
+	"github.com/tokopedia/sauron/src/elastic"
+)
+
+const testIndex = "promo-order-usage"
+
+var (
+	testModule Method
+	testServer fake.Method
+)
+
+type searchResponse struct {
+	Hits struct {
+		Total struct {
+			Value int `json:"value"`
+		} `json:"total"`
+		Hits []struct {
+			ID     string            `json:"_id"`
+			Source marketplace.Promo `json:"_source"`
+		} `json:"hits"`
+	} `json:"hits"`
+}
+
+func TestMain(m *testing.M) {
+	testServer = fake.New(fake.Config{})
+
+	module, err := New(Config{
+		Config: utils.Config{
+			Server: utils.ServerConfig{
+				Environment: "development",
+			},
+			ElasticSearch: utils.ElasticSearchConfig{
+				URL: testServer.URL(),
+			},
+		},
+	})
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	testModule = module
+
+	code := m.Run()
+	testServer.Close()
+	os.Exit(code)
+}
+
+func queryString(query string) elastic.Query {
+	return elastic.Query{
+		Bool: &elastic.Bool{
+			Must: []elastic.Must{
+				{QueryString: map[string]interface{}{"query": query}},
+			},
+		},
+	}
+}
+
+func TestGetInfo(t *testing.T) {
+	resp, err := testModule.GetInfo(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	var info struct {
+		Version struct {
+			Number string `json:"number"`
+		} `json:"version"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&info); err != nil {
+		t.Fatal(err)
+	}
+
+	if info.Version.Number != "7.5.1" {
+		t.Errorf("version = %q, want 7.5.1", info.Version.Number)
+	}
+}
+
+func TestDocumentLifecycle(t *testing.T) {
+	ctx := context.Background()
+	testServer.Reset()
+
+	promo := marketplace.Promo{
+		OrderID:    69696969,
+		Source:     "marketplace",
+		Platform:   "android",
+		Amount:     25000,
+		CreateTime: time.Date(2020, 5, 14, 20, 0, 0, 0, time.UTC),
+	}
+
+	if err := testModule.ProcessInsert(ctx, &elastic.InsertOption{
+		Environment: true,
+		Index:       testIndex,
+		ID:          "69696969",
+		Data:        promo,
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	// development writes to the staging indices
+	if _, ok := testServer.Get("staging-"+testIndex, "69696969"); !ok {
+		t.Fatal("inserted document not found in staging-" + testIndex)
+	}
+
+	promo.Platform = "ios"
+	if err := testModule.ProcessUpdate(ctx, &elastic.InsertOption{
+		Environment: true,
+		Index:       testIndex,
+		ID:          "69696969",
+		Data:        promo,
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	var resp searchResponse
+	if err := testModule.ProcessSearch(ctx, &elastic.SearchOption{
+		Environment: true,
+		Index:       testIndex,
+		Input:       queryString("source:marketplace AND platform:ios"),
+		Output:      &resp,
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(resp.Hits.Hits) != 1 || resp.Hits.Hits[0].Source.OrderID != promo.OrderID {
+		t.Fatalf("search = %+v, want order %d", resp.Hits.Hits, promo.OrderID)
+	}
+
+	count, err := testModule.ProcessCount(ctx, &elastic.SearchOption{
+		Environment: true,
+		Index:       testIndex,
+		Input:       queryString("platform:android"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 0 {
+		t.Errorf("count of the old platform = %d, want 0", count)
+	}
+
+	result, err := testModule.ProcessDelete(ctx, "69696969", &elastic.DeleteOption{
+		Environment: true,
+		Index:       testIndex,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result != "deleted" {
+		t.Errorf("delete result = %q, want deleted", result)
+	}
+
+	if _, err := testModule.ProcessDelete(ctx, "69696969", &elastic.DeleteOption{
+		Environment: true,
+		Index:       testIndex,
+	}); err == nil {
+		t.Error("deleting a missing document succeeded")
+	}
+}
+
+func TestProcessSearchRangeAndSort(t *testing.T) {
+	ctx := context.Background()
+	testServer.Reset()
+
+	jakarta := time.FixedZone("WIB", 7*60*60)
+	for i, created := range []time.Time{
+		time.Date(2020, 5, 13, 23, 30, 0, 0, jakarta),
+		time.Date(2020, 5, 14, 0, 30, 0, 0, jakarta),
+		time.Date(2020, 5, 14, 23, 30, 0, 0, jakarta),
+		time.Date(2020, 5, 15, 0, 30, 0, 0, jakarta),
+	} {
+		promo := marketplace.Promo{OrderID: int64(i + 1), Source: "marketplace", CreateTime: created}
+		if err := testServer.Put("staging-"+testIndex, fmt.Sprint(promo.OrderID), promo); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	query := queryString("source:marketplace")
+	query.Bool.Must = append(query.Bool.Must, elastic.Must{
+		Range: map[string]interface{}{
+			"create_time": map[string]interface{}{
+				"gte":       "2020-05-14",
+				"lte":       "2020-05-14",
+				"format":    "yyyy-MM-dd",
+				"time_zone": "+07:00",
+			},
+		},
+	})
+
+	var resp searchResponse
+	if err := testModule.ProcessSearch(ctx, &elastic.SearchOption{
+		Environment: true,
+		Index:       testIndex,
+		Input:       query,
+		Output:      &resp,
+		Sort:        map[string]interface{}{"create_time": "desc"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	var ids []string
+	for _, hit := range resp.Hits.Hits {
+		ids = append(ids, hit.ID)
+	}
+
+	if got := strings.Join(ids, ","); got != "3,2" {
+		t.Errorf("hits = %s, want 3,2", got)
+	}
+}
+
+func TestProcessBulk(t *testing.T) {
+	testServer.Reset()
+
+	var body strings.Builder
+	for i := 1; i <= 3; i++ {
+		fmt.Fprintf(&body, `{"index":{"_index":"staging-%s","_type":"order","_id":"%d"}}`+"\n", testIndex, i)
+		fmt.Fprintf(&body, `{"order_id":%d,"source":"marketplace"}`+"\n", i)
+	}
+	fmt.Fprintf(&body, `{"delete":{"_index":"staging-%s","_id":"2"}}`+"\n", testIndex)
+
+	if err := testModule.ProcessBulk(context.Background(), strings.NewReader(body.String())); err != nil {
+		t.Fatal(err)
+	}
+
+	if n := testServer.Count("staging-" + testIndex); n != 2 {
+		t.Errorf("documents after bulk = %d, want 2", n)
+	}
+}
@@ -0,0 +1,187 @@
+package fault
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/elastic-fray/entity/benchmark"
+)
+
+func New(c Config) Method {
+	if c.Transport == nil {
+		c.Transport = http.DefaultTransport
+	}
+
+	return Module{
+		config: c,
+		state: &state{
+			random:   rand.New(rand.NewSource(1)),
+			injected: map[string]int64{},
+		},
+	}
+}
+
+func (m Module) Wrap(next http.RoundTripper) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+
+	m.config.Transport = next
+	return m
+}
+
+func (m Module) Set(faults benchmark.Faults, seed int64) {
+	m.state.mutex.Lock()
+	defer m.state.mutex.Unlock()
+
+	m.state.faults = faults
+	m.state.random = rand.New(rand.NewSource(seed))
+	m.state.injected = map[string]int64{}
+}
+
+func (m Module) Injected() map[string]int64 {
+	m.state.mutex.Lock()
+	defer m.state.mutex.Unlock()
+
+	injected := make(map[string]int64, len(m.state.injected))
+	for k, v := range m.state.injected {
+		injected[k] = v
+	}
+
+	return injected
+}
+
+func (m Module) RoundTrip(r *http.Request) (*http.Response, error) {
+	d := m.state.draw()
+
+	if d.delay > 0 {
+		timer := time.NewTimer(d.delay)
+		select {
+		case <-r.Context().Done():
+			timer.Stop()
+			closeBody(r)
+			return nil, r.Context().Err()
+		case <-timer.C:
+		}
+	}
+
+	switch d.kind {
+	case KindReset:
+		closeBody(r)
+		return nil, &net.OpError{Op: "read", Net: "tcp", Err: os.NewSyscallError("read", syscall.ECONNRESET)}
+	case KindTooManyRequests:
+		closeBody(r)
+		return rejected(r, http.StatusTooManyRequests, "es_rejected_execution_exception", "rejected execution, queue capacity reached"), nil
+	case KindUnavailable:
+		closeBody(r)
+		return rejected(r, http.StatusServiceUnavailable, "cluster_block_exception", "blocked by: [SERVICE_UNAVAILABLE/2/no master];"), nil
+	}
+
+	resp, err := m.config.Transport.RoundTrip(r)
+	if err != nil || d.kind != KindTruncate {
+		return resp, err
+	}
+
+	// the declared length stays, the body ends half way
+	remaining := resp.ContentLength / 2
+	if resp.ContentLength < 0 {
+		remaining = 64
+	}
+	resp.Body = &truncated{body: resp.Body, remaining: remaining}
+
+	return resp, nil
+}
+
+func (s *state) draw() decision {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	var (
+		d = decision{}
+		f = s.faults
+	)
+
+	if f.Latency > 0 || f.Jitter > 0 {
+		if f.LatencyRate == 0 || s.random.Float64() < f.LatencyRate {
+			d.delay = f.Latency
+			if f.Jitter > 0 {
+				d.delay += time.Duration(s.random.Int63n(int64(f.Jitter)))
+			}
+			s.injected[KindLatency]++
+		}
+	}
+
+	// one draw for the error faults keeps them exclusive
+	x := s.random.Float64()
+	for _, fault := range []struct {
+		kind string
+		rate float64
+	}{
+		{KindReset, f.Reset},
+		{KindTooManyRequests, f.TooManyRequests},
+		{KindUnavailable, f.Unavailable},
+		{KindTruncate, f.Truncate},
+	} {
+		if x < fault.rate {
+			d.kind = fault.kind
+			s.injected[fault.kind]++
+			break
+		}
+		x -= fault.rate
+	}
+
+	return d
+}
+
+func rejected(r *http.Request, status int, kind, reason string) *http.Response {
+	body := fmt.Sprintf(`{"error":{"root_cause":[{"type":%q,"reason":%q}],"type":%q,"reason":%q},"status":%d}`,
+		kind, reason, kind, reason, status)
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", status, http.StatusText(status)),
+		StatusCode:    status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{"Content-Type": []string{"application/json; charset=UTF-8"}},
+		Body:          ioutil.NopCloser(strings.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       r,
+	}
+}
+
+func closeBody(r *http.Request) {
+	if r.Body != nil {
+		r.Body.Close()
+	}
+}
+
+func (t *truncated) Read(p []byte) (int, error) {
+	if t.remaining <= 0 {
+		return 0, io.ErrUnexpectedEOF
+	}
+
+	if int64(len(p)) > t.remaining {
+		p = p[:t.remaining]
+	}
+
+	n, err := t.body.Read(p)
+	t.remaining -= int64(n)
+	if err == io.EOF {
+		err = io.ErrUnexpectedEOF
+	}
+
+	return n, err
+}
+
+func (t *truncated) Close() error {
+	return t.body.Close()
+}
@@ -0,0 +1,128 @@
+package fault
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/elastic-fray/entity/benchmark"
+	"github.com/elastic-fray/pkg/elastic/fake"
+	"github.com/elastic-fray/pkg/proxy"
+)
+
+func TestFaults(t *testing.T) {
+	cluster := fake.New(fake.Config{})
+	defer cluster.Close()
+
+	faults := New(Config{})
+
+	// the official client uses the transport, the sauron client the proxy
+	direct := &http.Client{Transport: faults}
+	proxied := httptest.NewServer(proxy.New(proxy.Config{
+		Target:    cluster.URL(),
+		Transport: faults,
+	}))
+	defer proxied.Close()
+
+	for _, tc := range []struct {
+		name   string
+		faults benchmark.Faults
+		check  func(resp *http.Response, err error) error
+	}{
+		{"healthy", benchmark.Faults{}, status(http.StatusOK)},
+		{"too many requests", benchmark.Faults{TooManyRequests: 1}, status(http.StatusTooManyRequests)},
+		{"unavailable", benchmark.Faults{Unavailable: 1}, status(http.StatusServiceUnavailable)},
+		{"truncate", benchmark.Faults{Truncate: 1}, func(resp *http.Response, err error) error {
+			if err != nil {
+				return err
+			}
+			defer resp.Body.Close()
+
+			if _, err := ioutil.ReadAll(resp.Body); err == nil {
+				return errors.New("read the whole body")
+			}
+			return nil
+		}},
+		{"reset", benchmark.Faults{Reset: 1}, func(resp *http.Response, err error) error {
+			if err == nil {
+				resp.Body.Close()
+				return errors.New("got a response")
+			}
+			return nil
+		}},
+		{"latency", benchmark.Faults{Latency: 50 * time.Millisecond}, status(http.StatusOK)},
+	} {
+		for via, client := range map[string]*http.Client{"transport": direct, "proxy": http.DefaultClient} {
+			faults.Set(tc.faults, 1)
+
+			address := cluster.URL()
+			if via == "proxy" {
+				address = proxied.URL
+			}
+
+			start := time.Now()
+			resp, err := client.Get(address + "/")
+
+			if err := tc.check(resp, err); err != nil {
+				t.Errorf("%s through the %s: %v", tc.name, via, err)
+			}
+			if elapsed := time.Since(start); elapsed < tc.faults.Latency {
+				t.Errorf("%s through the %s took %s, want at least %s", tc.name, via, elapsed, tc.faults.Latency)
+			}
+		}
+	}
+}
+
+func TestResetError(t *testing.T) {
+	faults := New(Config{})
+	faults.Set(benchmark.Faults{Reset: 1}, 1)
+
+	_, err := faults.RoundTrip(httptest.NewRequest(http.MethodGet, "http://127.0.0.1:1/", nil))
+	if !errors.Is(err, syscall.ECONNRESET) {
+		t.Errorf("error = %v, want a connection reset", err)
+	}
+}
+
+func TestSetSeed(t *testing.T) {
+	faults := New(Config{}).(Module)
+
+	draws := func() []string {
+		faults.Set(benchmark.Faults{TooManyRequests: 0.3, Unavailable: 0.3}, 42)
+
+		var kinds []string
+		for i := 0; i < 50; i++ {
+			kinds = append(kinds, faults.state.draw().kind)
+		}
+		return kinds
+	}
+
+	first, second := draws(), draws()
+	for i := range first {
+		if first[i] != second[i] {
+			t.Fatalf("draw %d differs with the same seed: %q and %q", i, first[i], second[i])
+		}
+	}
+
+	injected := faults.Injected()
+	if injected[KindTooManyRequests] == 0 || injected[KindUnavailable] == 0 {
+		t.Errorf("injected = %v, want both kinds", injected)
+	}
+}
+
+func status(want int) func(resp *http.Response, err error) error {
+	return func(resp *http.Response, err error) error {
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != want {
+			return errors.New(resp.Status)
+		}
+		return nil
+	}
+}
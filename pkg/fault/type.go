@@ -0,0 +1,56 @@
+package fault
+
+import (
+	"io"
+	"math/rand"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/elastic-fray/entity/benchmark"
+)
+
+const (
+	KindLatency         = "latency"
+	KindTooManyRequests = "too_many_requests"
+	KindUnavailable     = "unavailable"
+	KindTruncate        = "truncate"
+	KindReset           = "reset"
+)
+
+type (
+	Method interface {
+		RoundTrip(r *http.Request) (*http.Response, error)
+		Wrap(next http.RoundTripper) http.RoundTripper
+		Set(faults benchmark.Faults, seed int64)
+		Injected() map[string]int64
+	}
+)
+
+type (
+	Config struct {
+		Transport http.RoundTripper // where healthy requests go, http.DefaultTransport when nil
+	}
+
+	Module struct {
+		config Config
+		state  *state
+	}
+
+	state struct {
+		mutex    sync.Mutex
+		faults   benchmark.Faults
+		random   *rand.Rand
+		injected map[string]int64
+	}
+
+	decision struct {
+		delay time.Duration
+		kind  string
+	}
+
+	truncated struct {
+		body      io.ReadCloser
+		remaining int64
+	}
+)
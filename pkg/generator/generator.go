@@ -0,0 +1,250 @@
+package generator
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"strings"
+	"time"
+
+	"github.com/elastic-fray/entity/promo/marketplace"
+	"github.com/elastic-fray/entity/user"
+)
+
+var (
+	sources = []choice{
+		{"marketplace", 80},
+		{"digital", 15},
+		{"official_store", 5},
+	}
+
+	platforms = []choice{
+		{"android", 55},
+		{"ios", 25},
+		{"desktop", 12},
+		{"mobile_web", 8},
+	}
+
+	benefits = []choice{
+		{"cashback", 70},
+		{"discount", 30},
+	}
+
+	gatewayCodes = []choice{
+		{"OVO", 40},
+		{"BCA_VA", 20},
+		{"GOPAY", 15},
+		{"CREDITCARD", 15},
+		{"ALFAMART", 10},
+	}
+
+	paymentGateways = []int{1, 5, 8, 11, 13}
+	shippings       = []int{1, 2, 4, 6, 10, 14, 23}
+)
+
+func New(c Config) Method {
+	if c.Location == nil {
+		c.Location = time.Local
+	}
+
+	if c.Anchor.IsZero() {
+		now := time.Now().In(c.Location)
+		c.Anchor = time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, c.Location)
+	}
+
+	if c.Window <= 0 {
+		c.Window = 30 * 24 * time.Hour
+	}
+
+	return Module{
+		config: c,
+	}
+}
+
+func (m Module) Promo(orderID int64) marketplace.Promo {
+	r := rand.New(rand.NewSource(m.config.Seed*1000003 + orderID))
+
+	createTime := m.createTime(r)
+	amount := amount(r)
+	shopID := 1 + r.Int63n(5000000)
+
+	return marketplace.Promo{
+		OrderID:          orderID,
+		PaymentID:        orderID*10 + r.Int63n(10),
+		ShopID:           shopID,
+		InvoiceRefNum:    fmt.Sprintf("INV/%s/XX/V/%d", createTime.Format("20060102"), orderID),
+		PaymentGatewayID: paymentGateways[r.Intn(len(paymentGateways))],
+		Amount:           amount,
+		ShippingID:       shippings[r.Intn(len(shippings))],
+		IsGoldShop:       r.Float64() < 0.3,
+		SellerData: user.UserData{
+			UserID: 1 + r.Int63n(10000000),
+		},
+		BuyerData: user.UserData{
+			UserID: 1 + r.Int63n(50000000),
+		},
+		PromoDetail: m.promoData(r, amount, createTime),
+		DeviceID:    hex(r, 16),
+		CreateTime:  createTime,
+		Source:      pick(r, sources),
+		Platform:    pick(r, platforms),
+		GroupID:     r.Intn(4),
+	}
+}
+
+func (m Module) Promos(orderID int64, n int) []marketplace.Promo {
+	promos := make([]marketplace.Promo, n)
+
+	for i := range promos {
+		promos[i] = m.Promo(orderID + int64(i))
+	}
+
+	return promos
+}
+
+func (m Module) promoData(r *rand.Rand, amount float64, createTime time.Time) marketplace.PromoData {
+	var (
+		benefit       = pick(r, benefits)
+		code          = voucherCode(r)
+		percentage    = float64(5 * (1 + r.Intn(10))) // 5% to 50%
+		maxPercentage = math.Min(percentage+float64(5*r.Intn(4)), 100)
+		benefitAmount = math.Min(round100(amount*percentage/100), float64(10000*(1+r.Intn(50))))
+		promoID       = 1 + r.Int63n(100000)
+		days          = 1 + r.Intn(30)
+	)
+
+	data := marketplace.PromoData{
+		PromoID:              promoID,
+		PromoName:            fmt.Sprintf("%s %d%% %s", strings.ToUpper(benefit), int(percentage), code),
+		VoucherCode:          code,
+		AdsID:                hex(r, 12),
+		Benefit:              benefit,
+		FingerPrint:          hex(r, 32),
+		GatewayCode:          pick(r, gatewayCodes),
+		IsBackdoor:           r.Float64() < 0.01,
+		IsUnlimited:          r.Float64() < 0.1,
+		PromoCodeUsageID:     1 + r.Intn(10000000),
+		PromoCodeID:          1 + r.Intn(100000),
+		BinaryPromoType:      1 << uint(r.Intn(6)),
+		ProductCode:          fmt.Sprintf("PRD-%05d", r.Intn(100000)),
+		Status:               1 + r.Intn(3),
+		Code:                 code,
+		Similarity:           math.Round(r.Float64()*100) / 100,
+		AdsIDChecking:        r.Float64() < 0.5,
+		IsPostCheck:          r.Float64() < 0.5,
+		IsCoupon:             r.Float64() < 0.2,
+		GroupID:              r.Intn(4),
+		IsExclusive:          r.Float64() < 0.05,
+		IsFraud:              r.Float64() < 0.005,
+		IsMaxBenefit:         benefitAmount < round100(amount*percentage/100),
+		BenefitAmount:        benefitAmount,
+		BenefitPercentage:    percentage,
+		MaxBenefitPercentage: maxPercentage,
+		TokoPointsRate:       float64(1 + r.Intn(5)),
+		PromoRule: &marketplace.PromoRule{
+			Counter: 1 + r.Intn(1000),
+			Expired: createTime.AddDate(0, 0, days).Unix(),
+			Status:  1 + r.Intn(2),
+			Days:    days,
+			Coverage: &marketplace.Coverage{
+				Categories: ints(r, r.Intn(6), 5000),
+				Products:   products(r, r.Intn(4)),
+				ServiceIds: int64s(r, r.Intn(3), 100),
+				PromoID:    int(promoID),
+			},
+		},
+	}
+
+	if benefit == "cashback" {
+		data.CashbackEarned = benefitAmount
+		data.TokoPointsEarned = math.Floor(benefitAmount / 100 * data.TokoPointsRate)
+	} else {
+		data.DiscountAmount = benefitAmount
+	}
+
+	return data
+}
+
+func (m Module) createTime(r *rand.Rand) time.Time {
+	day := m.config.Anchor.Add(-m.config.Window).Add(time.Duration(r.Int63n(int64(m.config.Window))))
+	day = time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, m.config.Location)
+
+	hour := int(math.Mod(20+r.NormFloat64()*4, 24)+24) % 24
+	offset := time.Duration(hour)*time.Hour + time.Duration(r.Int63n(int64(time.Hour)))
+
+	return day.Add(offset).Truncate(time.Second)
+}
+
+func amount(r *rand.Rand) float64 {
+	value := math.Exp(11.5 + r.NormFloat64())
+	return math.Max(1000, math.Min(round100(value), 50000000))
+}
+
+func round100(value float64) float64 {
+	return math.Round(value/100) * 100
+}
+
+func pick(r *rand.Rand, choices []choice) string {
+	var total int
+	for _, c := range choices {
+		total += c.weight
+	}
+
+	n := r.Intn(total)
+	for _, c := range choices {
+		if n -= c.weight; n < 0 {
+			return c.value
+		}
+	}
+
+	return choices[len(choices)-1].value
+}
+
+func voucherCode(r *rand.Rand) string {
+	const letters = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+
+	b := make([]byte, 8+r.Intn(5))
+	for i := range b {
+		b[i] = letters[r.Intn(len(letters))]
+	}
+
+	return string(b)
+}
+
+func hex(r *rand.Rand, n int) string {
+	const digits = "0123456789abcdef"
+
+	b := make([]byte, n)
+	for i := range b {
+		b[i] = digits[r.Intn(len(digits))]
+	}
+
+	return string(b)
+}
+
+func ints(r *rand.Rand, n, max int) []int {
+	var list []int
+	for i := 0; i < n; i++ {
+		list = append(list, 1+r.Intn(max))
+	}
+
+	return list
+}
+
+func int64s(r *rand.Rand, n int, max int64) []int64 {
+	var list []int64
+	for i := 0; i < n; i++ {
+		list = append(list, 1+r.Int63n(max))
+	}
+
+	return list
+}
+
+func products(r *rand.Rand, n int) []string {
+	var list []string
+	for i := 0; i < n; i++ {
+		list = append(list, fmt.Sprintf("PRD-%05d", r.Intn(100000)))
+	}
+
+	return list
+}
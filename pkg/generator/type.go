@@ -0,0 +1,32 @@
+package generator
+
+import (
+	"time"
+
+	"github.com/elastic-fray/entity/promo/marketplace"
+)
+
+type (
+	Method interface {
+		Promo(orderID int64) marketplace.Promo
+		Promos(orderID int64, n int) []marketplace.Promo
+	}
+)
+
+type (
+	Config struct {
+		Seed     int64
+		Location *time.Location
+		Anchor   time.Time     // create times fall in Window before it, defaults to the start of today
+		Window   time.Duration // defaults to 30 days
+	}
+
+	Module struct {
+		config Config
+	}
+
+	choice struct {
+		value  string
+		weight int
+	}
+)
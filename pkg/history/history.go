@@ -0,0 +1,202 @@
+package history
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/elastic-fray/entity/benchmark"
+)
+
+const runFile = "run.json"
+
+func New(c Config) Method {
+	if c.Dir == "" {
+		c.Dir = "results"
+	}
+
+	return Module{
+		config: c,
+	}
+}
+
+func (m Module) Save(run benchmark.Run) (string, error) {
+	if run.ID == "" {
+		run.ID = ID(run.StartedAt, run.Revision)
+	}
+
+	dir := filepath.Join(m.config.Dir, run.ID)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", err
+	}
+
+	data, err := json.MarshalIndent(run, "", "  ")
+	if err != nil {
+		return "", err
+	}
+
+	return run.ID, ioutil.WriteFile(filepath.Join(dir, runFile), data, 0644)
+}
+
+func (m Module) Load(id string) (benchmark.Run, error) {
+	var run benchmark.Run
+
+	if id == "latest" {
+		runs, err := m.List()
+		if err != nil {
+			return run, err
+		}
+
+		if len(runs) == 0 {
+			return run, fmt.Errorf("no runs in %s", m.config.Dir)
+		}
+
+		return runs[len(runs)-1], nil
+	}
+
+	data, err := ioutil.ReadFile(filepath.Join(m.config.Dir, id, runFile))
+	if err != nil {
+		return run, err
+	}
+
+	err = json.Unmarshal(data, &run)
+	return run, err
+}
+
+func (m Module) List() ([]benchmark.Run, error) {
+	entries, err := ioutil.ReadDir(m.config.Dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var runs []benchmark.Run
+
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+
+		run, err := m.Load(entry.Name())
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		runs = append(runs, run)
+	}
+
+	sort.Slice(runs, func(i, j int) bool {
+		return runs[i].StartedAt.Before(runs[j].StartedAt)
+	})
+
+	return runs, nil
+}
+
+func (m Module) Compare(baseline, candidate benchmark.Run, threshold float64) []benchmark.Regression {
+	var (
+		regressions []benchmark.Regression
+		base        = make(map[string]benchmark.Stats)
+	)
+
+	for _, result := range baseline.Results {
+		for _, s := range result.Stats {
+			base[key(result.Scenario, s)] = s
+		}
+	}
+
+	for _, result := range candidate.Results {
+		for _, s := range result.Stats {
+			b, ok := base[key(result.Scenario, s)]
+			if !ok {
+				continue
+			}
+
+			for _, metric := range []struct {
+				name            string
+				baseline, value float64
+				higherIsBetter  bool
+			}{
+				{MetricMean, float64(b.Mean), float64(s.Mean), false},
+				{MetricP50, float64(b.P50), float64(s.P50), false},
+				{MetricP99, float64(b.P99), float64(s.P99), false},
+				{MetricThroughput, b.Throughput(), s.Throughput(), true},
+				{MetricErrorRate, errorRate(b), errorRate(s), false},
+			} {
+				change := relative(metric.baseline, metric.value)
+				if metric.name == MetricErrorRate {
+					change = points(metric.baseline, metric.value)
+				}
+
+				worse := metric.value > metric.baseline
+				if metric.higherIsBetter {
+					worse = metric.value < metric.baseline
+				}
+
+				// from 0 any change is infinite, so it is flagged whatever the threshold
+				if !worse || change != nil && math.Abs(*change) <= threshold {
+					continue
+				}
+
+				regressions = append(regressions, benchmark.Regression{
+					Scenario:  result.Scenario,
+					Client:    s.Client,
+					Operation: s.Operation,
+					Metric:    metric.name,
+					Baseline:  metric.baseline,
+					Candidate: metric.value,
+					Change:    change,
+				})
+			}
+		}
+	}
+
+	return regressions
+}
+
+func ID(start time.Time, revision string) string {
+	id := start.Format("20060102-150405")
+	if revision != "" {
+		id += "-" + revision
+	}
+
+	return id
+}
+
+func key(scenario string, s benchmark.Stats) string {
+	return scenario + "/" + s.Client + "/" + s.Operation
+}
+
+func errorRate(s benchmark.Stats) float64 {
+	if s.Count == 0 {
+		return 0
+	}
+
+	return float64(s.Errors) / float64(s.Count)
+}
+
+func relative(baseline, value float64) *float64 {
+	var d float64
+
+	if baseline != 0 {
+		d = (value - baseline) / baseline * 100
+	} else if value != 0 {
+		return nil
+	}
+
+	return &d
+}
+
+func points(baseline, value float64) *float64 {
+	d := (value - baseline) * 100
+	return &d
+}
@@ -0,0 +1,156 @@
+package history
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/elastic-fray/entity/benchmark"
+)
+
+func TestSaveLoadList(t *testing.T) {
+	store := New(Config{
+		Dir: t.TempDir(),
+	})
+
+	if runs, err := store.List(); err != nil || len(runs) != 0 {
+		t.Fatalf("empty directory lists %v, %v", runs, err)
+	}
+
+	start := time.Date(2020, 5, 14, 10, 15, 0, 0, time.UTC)
+
+	// saved out of order, listed by start
+	for _, offset := range []time.Duration{time.Hour, 0, 2 * time.Hour} {
+		if _, err := store.Save(benchmark.Run{StartedAt: start.Add(offset), Revision: "3c036aa"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	runs, err := store.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var ids []string
+	for _, run := range runs {
+		ids = append(ids, run.ID)
+	}
+
+	want := []string{"20200514-101500-3c036aa", "20200514-111500-3c036aa", "20200514-121500-3c036aa"}
+	if len(ids) != len(want) {
+		t.Fatalf("runs = %v, want %v", ids, want)
+	}
+	for i := range want {
+		if ids[i] != want[i] {
+			t.Fatalf("runs = %v, want %v", ids, want)
+		}
+	}
+
+	latest, err := store.Load("latest")
+	if err != nil || latest.ID != want[2] {
+		t.Errorf("latest = %s, %v, want %s", latest.ID, err, want[2])
+	}
+
+	run, err := store.Load(want[0])
+	if err != nil || !run.StartedAt.Equal(start) {
+		t.Errorf("load = %v, %v, want the run started at %v", run.StartedAt, err, start)
+	}
+
+	if _, err := store.Load("20200101-000000"); !os.IsNotExist(err) {
+		t.Errorf("load of an unknown run = %v, want a not exist error", err)
+	}
+}
+
+func TestListSkipsOtherDirectories(t *testing.T) {
+	dir := t.TempDir()
+	store := New(Config{
+		Dir: dir,
+	})
+
+	if _, err := store.Save(benchmark.Run{ID: "run"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Mkdir(filepath.Join(dir, "profiles"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	if runs, err := store.List(); err != nil || len(runs) != 1 {
+		t.Errorf("runs = %v, %v, want the saved one only", runs, err)
+	}
+}
+
+func TestCompare(t *testing.T) {
+	baseline := run(benchmark.Stats{
+		Count:   1000,
+		Errors:  10,
+		Elapsed: 10 * time.Second,
+		Mean:    10 * time.Millisecond,
+		P50:     10 * time.Millisecond,
+		P99:     0,
+	})
+
+	for _, tc := range []struct {
+		name      string
+		candidate benchmark.Stats
+		want      map[string]*float64
+	}{
+		{"same", baseline.Results[0].Stats[0], nil},
+		{"within the threshold", benchmark.Stats{
+			Count: 1000, Errors: 50, Elapsed: 10 * time.Second, Mean: 11 * time.Millisecond, P50: 9 * time.Millisecond,
+		}, nil},
+		{"over the threshold", benchmark.Stats{
+			Count: 1000, Errors: 200, Elapsed: 20 * time.Second, Mean: 12 * time.Millisecond, P50: 10 * time.Millisecond,
+		}, map[string]*float64{
+			MetricMean:       float(20),
+			MetricThroughput: float(-50),
+			MetricErrorRate:  float(19),
+		}},
+		{"better", benchmark.Stats{
+			Count: 1000, Elapsed: 5 * time.Second, Mean: 5 * time.Millisecond, P50: 5 * time.Millisecond,
+		}, nil},
+		{"from 0", benchmark.Stats{
+			Count: 1000, Errors: 10, Elapsed: 10 * time.Second, Mean: 10 * time.Millisecond, P50: 10 * time.Millisecond, P99: time.Millisecond,
+		}, map[string]*float64{
+			MetricP99: nil,
+		}},
+	} {
+		regressions := New(Config{}).Compare(baseline, run(tc.candidate), 15)
+
+		got := make(map[string]*float64)
+		for _, r := range regressions {
+			got[r.Metric] = r.Change
+		}
+
+		if len(got) != len(tc.want) {
+			t.Errorf("%s: regressions = %v, want %v", tc.name, regressions, tc.want)
+			continue
+		}
+
+		for metric, want := range tc.want {
+			change, ok := got[metric]
+			switch {
+			case !ok:
+				t.Errorf("%s: %s not flagged", tc.name, metric)
+			case want == nil && change != nil:
+				t.Errorf("%s: %s change = %v, want n/a", tc.name, metric, *change)
+			case want != nil && (change == nil || *change-*want > 1e-9 || *want-*change > 1e-9):
+				t.Errorf("%s: %s change = %v, want %v", tc.name, metric, change, *want)
+			}
+		}
+	}
+}
+
+func run(s benchmark.Stats) benchmark.Run {
+	s.Client, s.Operation = "api", "search"
+
+	return benchmark.Run{
+		Results: []benchmark.Result{
+			{Scenario: "default", Stats: []benchmark.Stats{s}},
+		},
+	}
+}
+
+func float(f float64) *float64 {
+	return &f
+}
@@ -0,0 +1,32 @@
+package history
+
+import (
+	"github.com/elastic-fray/entity/benchmark"
+)
+
+const (
+	MetricMean       = "mean"
+	MetricP50        = "p50"
+	MetricP99        = "p99"
+	MetricThroughput = "throughput"
+	MetricErrorRate  = "error_rate"
+)
+
+type (
+	Method interface {
+		Save(run benchmark.Run) (string, error)
+		Load(id string) (benchmark.Run, error)
+		List() ([]benchmark.Run, error)
+		Compare(baseline, candidate benchmark.Run, threshold float64) []benchmark.Regression
+	}
+)
+
+type (
+	Config struct {
+		Dir string
+	}
+
+	Module struct {
+		config Config
+	}
+)
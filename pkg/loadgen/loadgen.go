@@ -0,0 +1,167 @@
+package loadgen
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+func New(c Config) Method {
+	if c.Workers < 1 {
+		c.Workers = 1
+	}
+
+	return Module{
+		config: c,
+	}
+}
+
+func (m Module) Run(ctx context.Context, job Job) error {
+	if m.config.Duration <= 0 && m.config.Iterations <= 0 {
+		return errors.New("loadgen: either duration or iterations must be set")
+	}
+
+	if m.config.OpenLoop && m.config.QPS <= 0 {
+		return errors.New("loadgen: an open loop needs a rate")
+	}
+
+	// the deadline only stops new jobs, the running ones finish with ctx so
+	// the end of the window does not turn them into errors
+	dispatch := ctx
+	if m.config.Duration > 0 {
+		var cancel context.CancelFunc
+		dispatch, cancel = context.WithTimeout(ctx, m.config.Duration)
+		defer cancel()
+	}
+
+	var (
+		wg    sync.WaitGroup
+		start = time.Now()
+	)
+
+	if m.config.OpenLoop {
+		m.openLoop(ctx, dispatch, start, job)
+	} else if m.config.QPS > 0 {
+		tokens := make(chan int64)
+		go m.pace(dispatch, start, tokens)
+
+		for i := 0; i < m.config.Workers; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+
+				for sequence := range tokens {
+					job(ctx, sequence, time.Now())
+				}
+			}()
+		}
+	} else {
+		var sequence int64 = -1
+
+		for i := 0; i < m.config.Workers; i++ {
+			wg.Add(1)
+			go func(delay time.Duration) {
+				defer wg.Done()
+
+				if !sleep(dispatch, delay) {
+					return
+				}
+
+				for dispatch.Err() == nil {
+					n := atomic.AddInt64(&sequence, 1)
+					if m.config.Iterations > 0 && n >= m.config.Iterations {
+						return
+					}
+
+					job(ctx, n, time.Now())
+				}
+			}(m.config.RampUp * time.Duration(i) / time.Duration(m.config.Workers))
+		}
+	}
+
+	wg.Wait()
+
+	return nil
+}
+
+func (m Module) openLoop(ctx, dispatch context.Context, start time.Time, job Job) {
+	var (
+		wg      sync.WaitGroup
+		workers = make(chan struct{}, m.config.Workers)
+	)
+
+	for n := int64(0); m.config.Iterations <= 0 || n < m.config.Iterations; n++ {
+		due := start.Add(m.offset(n))
+		if !sleep(dispatch, time.Until(due)) {
+			break
+		}
+
+		wg.Add(1)
+		go func(sequence int64) {
+			defer wg.Done()
+
+			select {
+			case workers <- struct{}{}:
+			case <-dispatch.Done():
+				return
+			}
+			defer func() { <-workers }()
+
+			job(ctx, sequence, due)
+		}(n)
+	}
+
+	wg.Wait()
+}
+
+func (m Module) pace(ctx context.Context, start time.Time, tokens chan<- int64) {
+	defer close(tokens)
+
+	for n := int64(0); m.config.Iterations <= 0 || n < m.config.Iterations; n++ {
+		if !sleep(ctx, time.Until(start.Add(m.offset(n)))) {
+			return
+		}
+
+		select {
+		case tokens <- n:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (m Module) offset(n int64) time.Duration {
+	var (
+		qps    = m.config.QPS
+		rampUp = m.config.RampUp.Seconds()
+		ramped = qps * rampUp / 2 // requests sent during the ramp-up
+		at     float64
+	)
+
+	if float64(n) < ramped {
+		at = math.Sqrt(2 * rampUp * float64(n) / qps)
+	} else {
+		at = rampUp + (float64(n)-ramped)/qps
+	}
+
+	return time.Duration(at * float64(time.Second))
+}
+
+func sleep(ctx context.Context, d time.Duration) bool {
+	if d <= 0 {
+		return ctx.Err() == nil
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
@@ -0,0 +1,115 @@
+package loadgen
+
+import (
+	"context"
+	"math"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestPace(t *testing.T) {
+	var count int64
+
+	err := New(Config{
+		Workers:  4,
+		QPS:      200,
+		Duration: 500 * time.Millisecond,
+	}).Run(context.Background(), func(ctx context.Context, sequence int64, due time.Time) {
+		atomic.AddInt64(&count, 1)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if rate := float64(count) / 0.5; math.Abs(rate-200) > 40 {
+		t.Errorf("sent %d requests, %.0f/s, want 200/s", count, rate)
+	}
+}
+
+func TestOpenLoopKeepsSchedule(t *testing.T) {
+	var (
+		mutex sync.Mutex
+		dues  = make(map[int64]time.Time)
+		late  = make(map[int64]time.Duration)
+	)
+
+	err := New(Config{
+		Workers:    1,
+		QPS:        100,
+		Iterations: 10,
+		OpenLoop:   true,
+	}).Run(context.Background(), func(ctx context.Context, sequence int64, due time.Time) {
+		mutex.Lock()
+		dues[sequence], late[sequence] = due, time.Since(due)
+		mutex.Unlock()
+
+		if sequence == 0 {
+			time.Sleep(100 * time.Millisecond)
+		}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(dues) != 10 {
+		t.Fatalf("ran %d jobs, want 10", len(dues))
+	}
+
+	// the stall delays the starts, not the schedule
+	if rate := 9 / dues[9].Sub(dues[0]).Seconds(); math.Abs(rate-100) > 10 {
+		t.Errorf("requests were due at %.1f/s, want 100/s", rate)
+	}
+
+	// request 1 was due 10ms in and waited for the stalled worker
+	if late[1] < 80*time.Millisecond {
+		t.Errorf("request 1 started %v late, want the wait for the worker", late[1])
+	}
+}
+
+func TestOpenLoopNeedsRate(t *testing.T) {
+	err := New(Config{
+		Iterations: 1,
+		OpenLoop:   true,
+	}).Run(context.Background(), func(ctx context.Context, sequence int64, due time.Time) {})
+
+	if err == nil {
+		t.Error("open loop without a rate ran")
+	}
+}
+
+func TestWindowLetsJobsFinish(t *testing.T) {
+	var (
+		mutex     sync.Mutex
+		finished  int
+		cancelled int
+	)
+
+	err := New(Config{
+		Workers:  2,
+		Duration: 20 * time.Millisecond,
+	}).Run(context.Background(), func(ctx context.Context, sequence int64, due time.Time) {
+		// a request still running when the window closes
+		timer := time.NewTimer(50 * time.Millisecond)
+		defer timer.Stop()
+
+		select {
+		case <-timer.C:
+			mutex.Lock()
+			finished++
+			mutex.Unlock()
+		case <-ctx.Done():
+			mutex.Lock()
+			cancelled++
+			mutex.Unlock()
+		}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if cancelled != 0 || finished != 2 {
+		t.Errorf("%d jobs finished and %d were cancelled, want the 2 running ones to finish", finished, cancelled)
+	}
+}
@@ -0,0 +1,33 @@
+package loadgen
+
+import (
+	"context"
+	"time"
+)
+
+type (
+	Method interface {
+		Run(ctx context.Context, job Job) error
+	}
+
+	Job func(ctx context.Context, sequence int64, due time.Time)
+)
+
+type (
+	Config struct {
+		Workers    int
+		QPS        float64       // 0 means as fast as the workers can go
+		RampUp     time.Duration // linear ramp of the rate, or of worker starts when QPS is 0
+		Duration   time.Duration // stops new jobs, the running ones finish
+		Iterations int64         // total jobs, 0 means until Duration elapses
+
+		// OpenLoop keeps the QPS schedule whatever the completion times. A
+		// request due while all workers are busy waits for one, and its job
+		// gets the time it was due so the wait can be counted.
+		OpenLoop bool
+	}
+
+	Module struct {
+		config Config
+	}
+)
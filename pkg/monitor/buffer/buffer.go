@@ -0,0 +1,243 @@
+package buffer
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/elastic-fray/pkg/monitor"
+)
+
+func New(c Config) Method {
+	if c.Size <= 0 {
+		c.Size = 10000
+	}
+	if c.Interval <= 0 {
+		c.Interval = time.Second
+	}
+
+	m := Module{
+		next:   c.Monitor,
+		state:  &state{},
+		queue:  make(chan metric, c.Size),
+		flush:  make(chan chan struct{}),
+		closed: make(chan struct{}),
+	}
+
+	go m.run(c.Interval)
+
+	return m
+}
+
+func (m Module) SetHistogram(start time.Time, name string, tags []string) {
+	m.SetHistogramDuration(name, time.Since(start), tags)
+}
+
+func (m Module) SetHistogramDuration(name string, duration time.Duration, tags []string) {
+	m.add(metric{
+		kind:     kindDuration,
+		name:     name,
+		tags:     tags,
+		duration: duration,
+	})
+}
+
+func (m Module) SetHistogramValue(name string, value float64, tags []string) {
+	m.add(metric{
+		kind:  kindValue,
+		name:  name,
+		tags:  tags,
+		value: value,
+	})
+}
+
+func (m Module) SetDistribution(name string, value float64, tags []string) {
+	m.add(metric{
+		kind:  kindDistribution,
+		name:  name,
+		tags:  tags,
+		value: value,
+	})
+}
+
+func (m Module) SetGauge(name string, value float64, tags []string) {
+	m.add(metric{
+		kind:  kindGauge,
+		name:  name,
+		tags:  tags,
+		value: value,
+	})
+}
+
+func (m Module) SetCount(name string, tags []string) {
+	m.add(metric{
+		kind:  kindCount,
+		name:  name,
+		tags:  tags,
+		value: 1,
+	})
+}
+
+func (m Module) SetError(name string, err error, tags []string) {
+	if err == nil {
+		return
+	}
+
+	m.SetCount(name, monitor.ErrorTags(err, tags))
+}
+
+func (m Module) add(x metric) {
+	// held until the metric is queued, so Close cannot stop run in between
+	m.state.mutex.RLock()
+	defer m.state.mutex.RUnlock()
+
+	if m.state.done {
+		m.send(x)
+		return
+	}
+
+	// the caller may reuse its slice
+	x.tags = append([]string(nil), x.tags...)
+
+	select {
+	case m.queue <- x:
+	default:
+		atomic.AddInt64(&m.state.dropped, 1)
+		atomic.AddInt64(&m.state.unsent, 1)
+	}
+}
+
+func (m Module) Flush() {
+	done := make(chan struct{})
+
+	select {
+	case m.flush <- done:
+		<-done
+	case <-m.closed:
+	}
+}
+
+func (m Module) Close() {
+	m.state.once.Do(func() {
+		m.state.mutex.Lock()
+		m.state.done = true
+		m.state.mutex.Unlock()
+
+		m.Flush()
+		close(m.closed)
+	})
+}
+
+func (m Module) Dropped() int64 {
+	return atomic.LoadInt64(&m.state.dropped)
+}
+
+func (m Module) run(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	a := newAggregate()
+
+	for {
+		select {
+		case x := <-m.queue:
+			a.add(x)
+		case <-ticker.C:
+			m.sendAll(a)
+			a = newAggregate()
+		case done := <-m.flush:
+			m.drain(a)
+			m.sendAll(a)
+			a = newAggregate()
+			close(done)
+		case <-m.closed:
+			m.drain(a)
+			m.sendAll(a)
+			return
+		}
+	}
+}
+
+func (m Module) drain(a *aggregate) {
+	for {
+		select {
+		case x := <-m.queue:
+			a.add(x)
+		default:
+			return
+		}
+	}
+}
+
+func (m Module) sendAll(a *aggregate) {
+	for _, x := range a.samples {
+		m.send(x)
+	}
+
+	for _, k := range a.keys {
+		m.send(*a.latest[k])
+	}
+
+	if n := atomic.SwapInt64(&m.state.unsent, 0); n > 0 {
+		m.send(metric{
+			kind:  kindCount,
+			name:  MetricDropped,
+			value: float64(n),
+		})
+	}
+}
+
+func (m Module) send(x metric) {
+	switch x.kind {
+	case kindDuration:
+		m.next.SetHistogramDuration(x.name, x.duration, x.tags)
+	case kindValue:
+		m.next.SetHistogramValue(x.name, x.value, x.tags)
+	case kindDistribution:
+		m.next.SetDistribution(x.name, x.value, x.tags)
+	case kindGauge:
+		m.next.SetGauge(x.name, x.value, x.tags)
+	case kindCount:
+		if counter, ok := m.next.(monitor.CountMethod); ok {
+			counter.AddCount(x.name, int64(x.value), x.tags)
+			return
+		}
+
+		for i := 0; i < int(x.value); i++ {
+			m.next.SetCount(x.name, x.tags)
+		}
+	}
+}
+
+func newAggregate() *aggregate {
+	return &aggregate{
+		latest: make(map[string]*metric),
+	}
+}
+
+func (a *aggregate) add(x metric) {
+	if x.kind != kindCount && x.kind != kindGauge {
+		a.samples = append(a.samples, x)
+		return
+	}
+
+	tags := append([]string(nil), x.tags...)
+	sort.Strings(tags)
+
+	k := strconv.Itoa(x.kind) + "\x00" + x.name + "\x00" + strings.Join(tags, ",")
+
+	latest, ok := a.latest[k]
+	if !ok {
+		a.latest[k] = &x
+		a.keys = append(a.keys, k)
+		return
+	}
+
+	if x.kind == kindCount {
+		latest.value += x.value
+	} else {
+		latest.value = x.value
+	}
+}
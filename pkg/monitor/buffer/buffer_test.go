@@ -0,0 +1,167 @@
+package buffer
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/elastic-fray/pkg/monitor/memory"
+)
+
+func TestFlush(t *testing.T) {
+	next := memory.New()
+	m := New(Config{
+		Monitor:  next,
+		Interval: time.Hour,
+	})
+	defer m.Close()
+
+	m.SetHistogramDuration("search", 20*time.Millisecond, []string{"client:api"})
+	m.SetHistogramValue("search.hits", 42, nil)
+	m.SetGauge("workers", 8, nil)
+	m.SetGauge("workers", 4, nil)
+	m.SetError("search.error", context.DeadlineExceeded, []string{"client:api"})
+	m.SetError("search.error", nil, []string{"client:api"})
+
+	if names := next.Names(); len(names) != 0 {
+		t.Errorf("sent %v before the flush", names)
+	}
+
+	m.Flush()
+
+	if d := next.Durations("search", "client:api"); !reflect.DeepEqual(d, []time.Duration{20 * time.Millisecond}) {
+		t.Errorf("durations = %v, want [20ms]", d)
+	}
+	if v := next.Values("search.hits"); !reflect.DeepEqual(v, []float64{42}) {
+		t.Errorf("hits = %v, want [42]", v)
+	}
+	if v := next.Values("workers"); !reflect.DeepEqual(v, []float64{4}) {
+		t.Errorf("gauges = %v, want the last one only", v)
+	}
+	if n := next.Count("search.error", "error:timeout"); n != 1 {
+		t.Errorf("errors = %d, want 1", n)
+	}
+}
+
+type counter struct {
+	memory.Method
+	calls []string
+}
+
+func (c *counter) AddCount(name string, n int64, tags []string) {
+	c.calls = append(c.calls, fmt.Sprintf("%s %d %v", name, n, tags))
+}
+
+func TestCounts(t *testing.T) {
+	next := &counter{Method: memory.New()}
+	m := New(Config{
+		Monitor:  next,
+		Interval: time.Hour,
+	})
+	defer m.Close()
+
+	tags := []string{"client:api", "outcome:success"}
+	for i := 0; i < 100; i++ {
+		m.SetCount("requests", tags)
+	}
+	tags[0] = "client:nethttp" // reused by the caller
+	m.SetCount("requests", []string{"outcome:success", "client:api"})
+	m.SetCount("requests", []string{"client:officialclient"})
+
+	m.Flush()
+
+	want := []string{
+		"requests 101 [client:api outcome:success]",
+		"requests 1 [client:officialclient]",
+	}
+
+	if !reflect.DeepEqual(next.calls, want) {
+		t.Errorf("counts = %q, want %q", next.calls, want)
+	}
+}
+
+func TestDropped(t *testing.T) {
+	next := memory.New()
+
+	// not running yet, so the queue stays full
+	m := Module{
+		next:   next,
+		state:  &state{},
+		queue:  make(chan metric, 1),
+		flush:  make(chan chan struct{}),
+		closed: make(chan struct{}),
+	}
+
+	for i := 0; i < 3; i++ {
+		m.SetCount("requests", nil)
+	}
+
+	if n := m.Dropped(); n != 2 {
+		t.Errorf("dropped = %d, want 2", n)
+	}
+
+	go m.run(time.Hour)
+	m.Close()
+
+	if n := next.Count("requests"); n != 1 {
+		t.Errorf("requests = %d, want 1", n)
+	}
+	if n := next.Count(MetricDropped); n != 2 {
+		t.Errorf("%s = %d, want 2", MetricDropped, n)
+	}
+
+	// after Close the metrics go to the backend directly
+	m.SetCount("requests", nil)
+	m.Flush()
+
+	if n := next.Count("requests"); n != 2 {
+		t.Errorf("requests after close = %d, want 2", n)
+	}
+}
+
+func TestCloseKeepsEveryMetric(t *testing.T) {
+	next := memory.New()
+	m := New(Config{
+		Monitor:  next,
+		Interval: time.Hour,
+	})
+
+	var (
+		wg    sync.WaitGroup
+		sent  int64
+		stop  = make(chan struct{})
+		start = make(chan struct{})
+	)
+
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+			for j := 0; j < 1000; j++ {
+				select {
+				case <-stop:
+					return
+				default:
+				}
+				m.SetCount("requests", nil)
+				atomic.AddInt64(&sent, 1)
+			}
+		}()
+	}
+
+	// close while the workers are sending
+	close(start)
+	time.Sleep(time.Millisecond)
+	m.Close()
+	close(stop)
+	wg.Wait()
+
+	if n := next.Count("requests") + next.Count(MetricDropped); n != sent {
+		t.Errorf("requests = %d, want %d", n, sent)
+	}
+}
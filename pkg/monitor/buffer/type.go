@@ -0,0 +1,65 @@
+package buffer
+
+import (
+	"sync"
+	"time"
+
+	"github.com/elastic-fray/pkg/monitor"
+)
+
+const MetricDropped = "monitor.buffer.dropped"
+
+const (
+	kindDuration = iota
+	kindValue
+	kindDistribution
+	kindGauge
+	kindCount
+)
+
+type (
+	Method interface {
+		monitor.Method
+		Flush()         // sends what is queued and waits for it
+		Close()         // flushes and stops, later metrics go to the backend directly
+		Dropped() int64 // since New
+	}
+)
+
+type (
+	Config struct {
+		Monitor  monitor.Method // the backend
+		Size     int            // of the queue, 10000 when 0
+		Interval time.Duration  // between flushes, 1s when 0
+	}
+
+	Module struct {
+		next   monitor.Method
+		state  *state
+		queue  chan metric
+		flush  chan chan struct{}
+		closed chan struct{}
+	}
+
+	state struct {
+		once    sync.Once
+		mutex   sync.RWMutex
+		done    bool  // set by Close
+		dropped int64 // since New
+		unsent  int64 // since the last flush
+	}
+
+	metric struct {
+		kind     int
+		name     string
+		tags     []string
+		duration time.Duration
+		value    float64
+	}
+
+	aggregate struct {
+		samples []metric           // durations, values and distributions, in order
+		latest  map[string]*metric // counts summed in value and gauges, by kind, name and tags
+		keys    []string           // of latest, in order of arrival
+	}
+)
@@ -0,0 +1,47 @@
+package monitor
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"strings"
+	"syscall"
+)
+
+func ErrorClass(err error) string {
+	var (
+		netErr    net.Error
+		opErr     *net.OpError
+		syntaxErr *json.SyntaxError
+		typeErr   *json.UnmarshalTypeError
+	)
+
+	switch {
+	case errors.Is(err, context.DeadlineExceeded), errors.As(err, &netErr) && netErr.Timeout():
+		return ErrorTimeout
+	case errors.Is(err, context.Canceled):
+		return ErrorCanceled
+	case errors.Is(err, syscall.ECONNREFUSED), errors.Is(err, syscall.ECONNRESET), errors.As(err, &opErr):
+		return ErrorConnection
+	case errors.Is(err, io.EOF), errors.Is(err, io.ErrUnexpectedEOF):
+		return ErrorEOF
+	case errors.As(err, &syntaxErr), errors.As(err, &typeErr):
+		return ErrorDecode
+	}
+
+	// the clients do not all wrap what they got from net/http
+	switch message := err.Error(); {
+	case strings.Contains(message, "Client.Timeout exceeded"):
+		return ErrorTimeout
+	case strings.Contains(message, "connection reset"), strings.Contains(message, "connection refused"):
+		return ErrorConnection
+	}
+
+	return ErrorOther
+}
+
+func ErrorTags(err error, tags []string) []string {
+	return append(tags[:len(tags):len(tags)], TagError+":"+ErrorClass(err))
+}
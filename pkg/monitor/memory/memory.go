@@ -0,0 +1,283 @@
+package memory
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/elastic-fray/pkg/monitor"
+)
+
+func New() Method {
+	return Module{
+		state: &state{},
+	}
+}
+
+func (m Module) SetHistogram(start time.Time, name string, tags []string) {
+	now := time.Now()
+
+	m.add(Sample{
+		Name:     name,
+		Kind:     KindHistogram,
+		Tags:     tags,
+		Duration: now.Sub(start),
+		Time:     now,
+	})
+}
+
+func (m Module) SetHistogramDuration(name string, duration time.Duration, tags []string) {
+	m.add(Sample{
+		Name:     name,
+		Kind:     KindHistogram,
+		Tags:     tags,
+		Duration: duration,
+		Time:     time.Now(),
+	})
+}
+
+func (m Module) SetHistogramValue(name string, value float64, tags []string) {
+	m.addValue(KindValue, name, value, tags)
+}
+
+func (m Module) SetDistribution(name string, value float64, tags []string) {
+	m.addValue(KindDistribution, name, value, tags)
+}
+
+func (m Module) SetGauge(name string, value float64, tags []string) {
+	m.addValue(KindGauge, name, value, tags)
+}
+
+func (m Module) SetError(name string, err error, tags []string) {
+	if err == nil {
+		return
+	}
+
+	m.SetCount(name, monitor.ErrorTags(err, tags))
+}
+
+func (m Module) SetCount(name string, tags []string) {
+	m.add(Sample{
+		Name: name,
+		Kind: KindCount,
+		Tags: tags,
+		Time: time.Now(),
+	})
+}
+
+func (m Module) addValue(kind, name string, value float64, tags []string) {
+	m.add(Sample{
+		Name:  name,
+		Kind:  kind,
+		Tags:  tags,
+		Value: value,
+		Time:  time.Now(),
+	})
+}
+
+func (m Module) add(s Sample) {
+	// the caller may reuse its slice
+	s.Tags = append([]string(nil), s.Tags...)
+
+	m.state.mutex.Lock()
+	m.state.samples = append(m.state.samples, s)
+	m.state.mutex.Unlock()
+}
+
+func (m Module) Samples(name string, tags ...string) []Sample {
+	m.state.mutex.RLock()
+	defer m.state.mutex.RUnlock()
+
+	var samples []Sample
+
+	for _, s := range m.state.samples {
+		if s.Name == name && hasTags(s.Tags, tags) {
+			samples = append(samples, s)
+		}
+	}
+
+	return samples
+}
+
+func (m Module) Durations(name string, tags ...string) []time.Duration {
+	var durations []time.Duration
+
+	for _, s := range m.Samples(name, tags...) {
+		if s.Kind == KindHistogram {
+			durations = append(durations, s.Duration)
+		}
+	}
+
+	return durations
+}
+
+func (m Module) Values(name string, tags ...string) []float64 {
+	var values []float64
+
+	for _, s := range m.Samples(name, tags...) {
+		switch s.Kind {
+		case KindValue, KindDistribution, KindGauge:
+			values = append(values, s.Value)
+		}
+	}
+
+	return values
+}
+
+func (m Module) Count(name string, tags ...string) int64 {
+	return int64(len(m.Samples(name, tags...)))
+}
+
+func (m Module) Names() []string {
+	m.state.mutex.RLock()
+	defer m.state.mutex.RUnlock()
+
+	var (
+		names []string
+		seen  = make(map[string]bool)
+	)
+
+	for _, s := range m.state.samples {
+		if !seen[s.Name] {
+			seen[s.Name] = true
+			names = append(names, s.Name)
+		}
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+func (m Module) Summary() []Summary {
+	m.state.mutex.RLock()
+	defer m.state.mutex.RUnlock()
+
+	var (
+		keys      []string
+		summaries = make(map[string]*Summary)
+		values    = make(map[string][]float64)
+	)
+
+	for _, s := range m.state.samples {
+		tags := append([]string(nil), s.Tags...)
+		sort.Strings(tags)
+
+		k := s.Name + "\x00" + s.Kind + "\x00" + strings.Join(tags, ",")
+
+		summary, ok := summaries[k]
+		if !ok {
+			summary = &Summary{
+				Name: s.Name,
+				Kind: s.Kind,
+				Tags: tags,
+			}
+			summaries[k] = summary
+			keys = append(keys, k)
+		}
+
+		summary.Count++
+
+		switch s.Kind {
+		case KindHistogram:
+			values[k] = append(values[k], float64(s.Duration)/float64(time.Millisecond))
+		case KindValue, KindDistribution, KindGauge:
+			values[k] = append(values[k], s.Value)
+			summary.Last = s.Value
+		}
+	}
+
+	sort.Strings(keys)
+
+	list := make([]Summary, 0, len(keys))
+
+	for _, k := range keys {
+		summary := summaries[k]
+
+		if v := values[k]; len(v) > 0 {
+			sort.Float64s(v)
+
+			var sum float64
+			for _, value := range v {
+				sum += value
+			}
+
+			summary.Mean = sum / float64(len(v))
+			summary.P50 = percentile(v, 50)
+			summary.P99 = percentile(v, 99)
+			summary.Max = v[len(v)-1]
+		}
+
+		list = append(list, *summary)
+	}
+
+	return list
+}
+
+func (m Module) WriteSummary(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(tw, "METRIC\tKIND\tTAGS\tCOUNT\tMEAN\tP50\tP99\tMAX\tLAST\t")
+
+	for _, s := range m.Summary() {
+		tags := strings.Join(s.Tags, ",")
+		if tags == "" {
+			tags = "-"
+		}
+
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%d\t", s.Name, s.Kind, tags, s.Count)
+
+		switch s.Kind {
+		case KindCount:
+			fmt.Fprint(tw, "-\t-\t-\t-\t-\t\n")
+		case KindHistogram:
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t-\t\n", milliseconds(s.Mean), milliseconds(s.P50), milliseconds(s.P99), milliseconds(s.Max))
+		case KindGauge:
+			fmt.Fprintf(tw, "%g\t%g\t%g\t%g\t%g\t\n", s.Mean, s.P50, s.P99, s.Max, s.Last)
+		default:
+			fmt.Fprintf(tw, "%g\t%g\t%g\t%g\t-\t\n", s.Mean, s.P50, s.P99, s.Max)
+		}
+	}
+
+	return tw.Flush()
+}
+
+func (m Module) Reset() {
+	m.state.mutex.Lock()
+	m.state.samples = nil
+	m.state.mutex.Unlock()
+}
+
+func hasTags(tags, want []string) bool {
+	for _, w := range want {
+		found := false
+		for _, tag := range tags {
+			if tag == w {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			return false
+		}
+	}
+
+	return true
+}
+
+func percentile(sorted []float64, p float64) float64 {
+	i := int(math.Ceil(p/100*float64(len(sorted)))) - 1
+	if i < 0 {
+		i = 0
+	}
+
+	return sorted[i]
+}
+
+func milliseconds(ms float64) time.Duration {
+	return time.Duration(ms * float64(time.Millisecond)).Round(time.Microsecond)
+}
@@ -0,0 +1,132 @@
+package memory
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestQueries(t *testing.T) {
+	m := New()
+
+	m.SetHistogram(time.Now().Add(-20*time.Millisecond), "usecase.elastic.api.get.promo.order.usage", []string{"client:api", "status:ok"})
+	m.SetHistogram(time.Now().Add(-10*time.Millisecond), "usecase.elastic.api.get.promo.order.usage", []string{"client:api", "status:error"})
+	m.SetCount("usecase.elastic.api.error", []string{"client:api"})
+
+	if n := m.Count("usecase.elastic.api.get.promo.order.usage"); n != 2 {
+		t.Errorf("count = %d, want 2", n)
+	}
+	if n := m.Count("usecase.elastic.api.get.promo.order.usage", "status:error", "client:api"); n != 1 {
+		t.Errorf("count with status:error = %d, want 1", n)
+	}
+	if n := m.Count("usecase.elastic.api.get.promo.order.usage", "client:officialclient"); n != 0 {
+		t.Errorf("count of officialclient = %d, want 0", n)
+	}
+
+	durations := m.Durations("usecase.elastic.api.get.promo.order.usage", "status:ok")
+	if len(durations) != 1 || durations[0] < 20*time.Millisecond {
+		t.Errorf("durations = %v, want one of at least 20ms", durations)
+	}
+
+	if d := m.Durations("usecase.elastic.api.error"); len(d) != 0 {
+		t.Errorf("durations of a count = %v, want none", d)
+	}
+
+	m.SetHistogramValue("usecase.elastic.api.get.hits", 42, []string{"client:api"})
+	if v := m.Values("usecase.elastic.api.get.hits", "client:api"); !reflect.DeepEqual(v, []float64{42}) {
+		t.Errorf("values = %v, want [42]", v)
+	}
+
+	m.SetError("usecase.elastic.api.error", nil, []string{"client:api"})
+	m.SetError("usecase.elastic.api.error", context.DeadlineExceeded, []string{"client:api"})
+	if n := m.Count("usecase.elastic.api.error", "client:api", "error:timeout"); n != 1 {
+		t.Errorf("timeouts = %d, want 1", n)
+	}
+
+	want := []string{"usecase.elastic.api.error", "usecase.elastic.api.get.hits", "usecase.elastic.api.get.promo.order.usage"}
+	if names := m.Names(); !reflect.DeepEqual(names, want) {
+		t.Errorf("names = %v, want %v", names, want)
+	}
+
+	m.Reset()
+	if names := m.Names(); len(names) != 0 {
+		t.Errorf("names after reset = %v, want none", names)
+	}
+}
+
+func TestSummary(t *testing.T) {
+	m := New()
+
+	for i := 1; i <= 100; i++ {
+		m.(Module).add(Sample{
+			Name:     "search",
+			Kind:     KindHistogram,
+			Tags:     []string{"status:ok", "client:api"},
+			Duration: time.Duration(i) * time.Millisecond,
+		})
+	}
+	m.SetCount("search.error", nil)
+	m.SetCount("search.error", nil)
+	m.SetGauge("workers", 8, nil)
+	m.SetGauge("workers", 4, nil)
+	m.SetHistogramValue("search.hits", 3, nil)
+
+	want := []Summary{
+		{
+			Name:  "search",
+			Kind:  KindHistogram,
+			Tags:  []string{"client:api", "status:ok"},
+			Count: 100,
+			Mean:  50.5,
+			P50:   50,
+			P99:   99,
+			Max:   100,
+		},
+		{
+			Name:  "search.error",
+			Kind:  KindCount,
+			Count: 2,
+		},
+		{
+			Name:  "search.hits",
+			Kind:  KindValue,
+			Count: 1,
+			Mean:  3,
+			P50:   3,
+			P99:   3,
+			Max:   3,
+			Last:  3,
+		},
+		{
+			Name:  "workers",
+			Kind:  KindGauge,
+			Count: 2,
+			Mean:  6,
+			P50:   4,
+			P99:   8,
+			Max:   8,
+			Last:  4,
+		},
+	}
+
+	if got := m.Summary(); !reflect.DeepEqual(got, want) {
+		t.Errorf("summary = %+v, want %+v", got, want)
+	}
+
+	var out strings.Builder
+	if err := m.WriteSummary(&out); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, line := range []string{
+		"search  histogram  client:api,status:ok    100  50.5ms  50ms  99ms  100ms     -",
+		"search.error      count                     -      2       -     -     -      -     -",
+		"workers      gauge                     -      2       6     4     8      8     4",
+	} {
+		if !strings.Contains(out.String(), line) {
+			t.Errorf("summary lacks %q, got:\n%s", line, out.String())
+		}
+	}
+}
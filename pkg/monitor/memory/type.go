@@ -0,0 +1,63 @@
+package memory
+
+import (
+	"io"
+	"sync"
+	"time"
+
+	"github.com/elastic-fray/pkg/monitor"
+)
+
+const (
+	KindHistogram    = "histogram" // of durations
+	KindValue        = "value"     // histogram of values
+	KindDistribution = "distribution"
+	KindGauge        = "gauge"
+	KindCount        = "count"
+)
+
+type (
+	Method interface {
+		monitor.Method
+		Samples(name string, tags ...string) []Sample // of name carrying every tag, in the order they were recorded
+		Durations(name string, tags ...string) []time.Duration
+		Values(name string, tags ...string) []float64
+		Count(name string, tags ...string) int64
+		Names() []string
+		Summary() []Summary
+		WriteSummary(w io.Writer) error
+		Reset()
+	}
+)
+
+type (
+	Module struct {
+		state *state
+	}
+
+	state struct {
+		mutex   sync.RWMutex
+		samples []Sample
+	}
+
+	Sample struct {
+		Name     string
+		Kind     string
+		Tags     []string
+		Duration time.Duration // of a histogram sample
+		Value    float64       // of a value, distribution or gauge sample
+		Time     time.Time
+	}
+
+	Summary struct {
+		Name  string
+		Kind  string
+		Tags  []string
+		Count int64
+		Mean  float64
+		P50   float64
+		P99   float64
+		Max   float64
+		Last  float64
+	}
+)
@@ -9,11 +9,17 @@ import (
 )
 
 func New(c Config) Method {
-	return Module{
+	m := Module{
 		datadog: monitor.New(monitor.Config{
 			Client: c.Datadog,
 		}),
 	}
+
+	if c.Datadog != nil {
+		m.statsd = c.Datadog
+	}
+
+	return m
 }
 
 func (m Module) SetHistogram(start time.Time, name string, tags []string) {
@@ -25,6 +31,36 @@ func (m Module) SetHistogram(start time.Time, name string, tags []string) {
 	m.datadog.SetHistogram(start, name, tags)
 }
 
+func (m Module) SetHistogramDuration(name string, duration time.Duration, tags []string) {
+	m.SetHistogramValue(name, float64(duration)/float64(time.Millisecond), tags)
+}
+
+func (m Module) SetHistogramValue(name string, value float64, tags []string) {
+	if m.statsd == nil {
+		log.Error("Empty monitor datadog for metric: ", name)
+		return
+	}
+
+	if err := m.statsd.Histogram(name, value, tags, 1); err != nil {
+		log.Error(err)
+	}
+}
+
+func (m Module) SetDistribution(name string, value float64, tags []string) {
+	m.SetHistogramValue(name, value, tags)
+}
+
+func (m Module) SetGauge(name string, value float64, tags []string) {
+	if m.statsd == nil {
+		log.Error("Empty monitor datadog for metric: ", name)
+		return
+	}
+
+	if err := m.statsd.Gauge(name, value, tags, 1); err != nil {
+		log.Error(err)
+	}
+}
+
 func (m Module) SetCount(name string, tags []string) {
 	if m.datadog == nil {
 		log.Error("Empty monitor datadog for metric: ", name)
@@ -33,3 +69,22 @@ func (m Module) SetCount(name string, tags []string) {
 
 	m.datadog.SetCount(name, tags)
 }
+
+func (m Module) AddCount(name string, n int64, tags []string) {
+	if m.statsd == nil {
+		log.Error("Empty monitor datadog for metric: ", name)
+		return
+	}
+
+	if err := m.statsd.Count(name, n, tags, 1); err != nil {
+		log.Error(err)
+	}
+}
+
+func (m Module) SetError(name string, err error, tags []string) {
+	if err == nil {
+		return
+	}
+
+	m.SetCount(name, ErrorTags(err, tags))
+}
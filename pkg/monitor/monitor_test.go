@@ -0,0 +1,130 @@
+package monitor
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"reflect"
+	"syscall"
+	"testing"
+	"time"
+)
+
+type statsd struct {
+	calls []string
+}
+
+func (s *statsd) Gauge(name string, value float64, tags []string, rate float64) error {
+	s.calls = append(s.calls, fmt.Sprintf("gauge %s %g %v", name, value, tags))
+	return nil
+}
+
+func (s *statsd) Count(name string, value int64, tags []string, rate float64) error {
+	s.calls = append(s.calls, fmt.Sprintf("count %s %d %v", name, value, tags))
+	return nil
+}
+
+func (s *statsd) Histogram(name string, value float64, tags []string, rate float64) error {
+	s.calls = append(s.calls, fmt.Sprintf("histogram %s %g %v", name, value, tags))
+	return nil
+}
+
+func TestStatsd(t *testing.T) {
+	s := &statsd{}
+	m := Module{
+		statsd: s,
+	}
+
+	m.SetHistogramDuration("took", 1500*time.Microsecond, []string{"client:api"})
+	m.SetHistogramValue("hits", 20, nil)
+	m.SetDistribution("size", 3, nil)
+	m.SetGauge("workers", 8, nil)
+	m.AddCount("requests", 12, []string{"client:api"})
+
+	want := []string{
+		"histogram took 1.5 [client:api]",
+		"histogram hits 20 []",
+		"histogram size 3 []",
+		"gauge workers 8 []",
+		"count requests 12 [client:api]",
+	}
+
+	if !reflect.DeepEqual(s.calls, want) {
+		t.Errorf("calls = %q, want %q", s.calls, want)
+	}
+}
+
+func TestErrorClass(t *testing.T) {
+	var syntaxErr *json.SyntaxError
+	if err := json.Unmarshal([]byte("}"), &struct{}{}); !errors.As(err, &syntaxErr) {
+		t.Fatalf("no syntax error from %v", err)
+	}
+
+	for err, want := range map[error]string{
+		context.DeadlineExceeded: ErrorTimeout,
+		&url.Error{Op: "Post", URL: "http://replay", Err: context.DeadlineExceeded}: ErrorTimeout,
+		fmt.Errorf("search: %w", context.Canceled):                                  ErrorCanceled,
+		&net.OpError{Op: "dial", Err: syscall.ECONNREFUSED}:                         ErrorConnection,
+		errors.New("read tcp: connection reset by peer"):                            ErrorConnection,
+		io.ErrUnexpectedEOF:                   ErrorEOF,
+		syntaxErr:                             ErrorDecode,
+		errors.New("[429 Too Many Requests]"): ErrorOther,
+	} {
+		if got := ErrorClass(err); got != want {
+			t.Errorf("ErrorClass(%v) = %s, want %s", err, got, want)
+		}
+	}
+}
+
+func TestErrorTags(t *testing.T) {
+	tags := make([]string, 1, 2)
+	tags[0] = "client:api"
+
+	got := ErrorTags(io.EOF, tags)
+	if want := []string{"client:api", "error:eof"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("tags = %v, want %v", got, want)
+	}
+
+	if tags = tags[:cap(tags)]; tags[1] != "" {
+		t.Errorf("the tags of the caller were changed to %v", tags)
+	}
+}
+
+func TestCallTags(t *testing.T) {
+	for _, test := range []struct {
+		call Call
+		want []string
+	}{
+		{
+			call: Call{Client: "api", Operation: "search", Index: "promo-order-usage"},
+			want: []string{"client:api", "operation:search", "index:promo-order-usage", "status_code:unknown", "outcome:success"},
+		},
+		{
+			call: Call{Client: "nethttp", Operation: "bulk", Status: 429, Err: errors.New("[429 Too Many Requests]")},
+			want: []string{"client:nethttp", "operation:bulk", "status_code:429", "outcome:error", "error:other"},
+		},
+	} {
+		if got := test.call.Tags(); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("tags of %+v = %v, want %v", test.call, got, test.want)
+		}
+	}
+}
+
+func TestStatus(t *testing.T) {
+	SetStatus(context.Background(), 200) // no holder, nothing to note
+
+	ctx := WithStatus(context.Background())
+	if status := Status(ctx); status != 0 {
+		t.Errorf("status before a response = %d, want 0", status)
+	}
+
+	SetStatus(ctx, 503)
+	SetStatus(ctx, 200)
+	if status := Status(ctx); status != 200 {
+		t.Errorf("status = %d, want the last one, 200", status)
+	}
+}
@@ -0,0 +1,229 @@
+package prometheus
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"github.com/elastic-fray/pkg/monitor"
+
+	"github.com/tokopedia/tdk/go/log"
+)
+
+var labels = []string{
+	monitor.TagClient,
+	monitor.TagOperation,
+	monitor.TagIndex,
+	monitor.TagOutcome,
+	monitor.TagStatus,
+	monitor.TagError,
+}
+
+func New(c Config) Method {
+	if len(c.Buckets) == 0 {
+		c.Buckets = prometheus.DefBuckets
+	}
+	if len(c.Values) == 0 {
+		c.Values = prometheus.ExponentialBuckets(1, 2, 21)
+	}
+
+	registry := c.Registry
+	if registry == nil {
+		registry = prometheus.NewRegistry()
+		registry.MustRegister(
+			prometheus.NewGoCollector(),
+			prometheus.NewProcessCollector(prometheus.ProcessCollectorOpts{}),
+		)
+	}
+
+	return Module{
+		config:   c,
+		registry: registry,
+		state: &state{
+			metrics: make(map[string]*metric),
+		},
+	}
+}
+
+func (m Module) SetHistogram(start time.Time, name string, tags []string) {
+	m.SetHistogramDuration(name, time.Since(start), tags)
+}
+
+func (m Module) SetHistogramDuration(name string, duration time.Duration, tags []string) {
+	m.observe(m.name(name)+"_seconds", "Duration of "+name+" in seconds.", m.config.Buckets, duration.Seconds(), tags)
+}
+
+func (m Module) SetHistogramValue(name string, value float64, tags []string) {
+	m.observe(m.name(name), "Values of "+name+".", m.config.Values, value, tags)
+}
+
+func (m Module) SetDistribution(name string, value float64, tags []string) {
+	m.SetHistogramValue(name, value, tags)
+}
+
+func (m Module) SetGauge(name string, value float64, tags []string) {
+	gauge, label, ok := m.metric(m.name(name), tags, func(names []string) prometheus.Collector {
+		return prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: m.name(name),
+			Help: "Last value of " + name + ".",
+		}, names)
+	})
+	if ok {
+		gauge.(*prometheus.GaugeVec).WithLabelValues(label...).Set(value)
+	}
+}
+
+func (m Module) SetCount(name string, tags []string) {
+	m.AddCount(name, 1, tags)
+}
+
+func (m Module) AddCount(name string, n int64, tags []string) {
+	counter, label, ok := m.metric(m.name(name)+"_total", tags, func(names []string) prometheus.Collector {
+		return prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: m.name(name) + "_total",
+			Help: "Number of " + name + ".",
+		}, names)
+	})
+	if ok {
+		counter.(*prometheus.CounterVec).WithLabelValues(label...).Add(float64(n))
+	}
+}
+
+func (m Module) SetError(name string, err error, tags []string) {
+	if err == nil {
+		return
+	}
+
+	m.SetCount(name, monitor.ErrorTags(err, tags))
+}
+
+func (m Module) observe(name, help string, buckets []float64, value float64, tags []string) {
+	histogram, label, ok := m.metric(name, tags, func(names []string) prometheus.Collector {
+		return prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    name,
+			Help:    help,
+			Buckets: buckets,
+		}, names)
+	})
+	if ok {
+		histogram.(*prometheus.HistogramVec).WithLabelValues(label...).Observe(value)
+	}
+}
+
+func (m Module) metric(name string, tags []string, create func(names []string) prometheus.Collector) (prometheus.Collector, []string, bool) {
+	values := m.values(tags)
+
+	m.state.mutex.Lock()
+	defer m.state.mutex.Unlock()
+
+	c, ok := m.state.metrics[name]
+	if !ok {
+		c = newMetric(values)
+		c.collector = create(c.names)
+
+		if err := m.registry.Register(c.collector); err != nil {
+			log.Errorf("prometheus metric %s: %v", name, err)
+			c.collector = nil
+		}
+		m.state.metrics[name] = c
+	}
+
+	if c.collector == nil {
+		return nil, nil, false
+	}
+
+	return c.collector, c.values(name, values), true
+}
+
+func (m Module) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+}
+
+func (m Module) name(name string) string {
+	if m.config.Namespace == "" {
+		return sanitize(name)
+	}
+
+	return sanitize(m.config.Namespace) + "_" + sanitize(name)
+}
+
+func (m Module) values(tags []string) map[string]string {
+	values := make(map[string]string, len(m.config.Tags)+len(tags))
+
+	for _, list := range [][]string{m.config.Tags, tags} {
+		for _, tag := range list {
+			key, value := tag, "true"
+			if i := strings.Index(tag, ":"); i >= 0 {
+				key, value = tag[:i], tag[i+1:]
+			}
+
+			if key = sanitize(key); key != "" {
+				values[key] = value
+			}
+		}
+	}
+
+	return values
+}
+
+func newMetric(values map[string]string) *metric {
+	c := &metric{
+		dropped: make(map[string]bool),
+	}
+
+	// a metric keeps the labels it was registered with, so the standard
+	// ones are there whatever the first call sent
+	names := make(map[string]bool, len(labels)+len(values))
+	for _, name := range labels {
+		names[name] = true
+	}
+	for name := range values {
+		names[name] = true
+	}
+
+	for name := range names {
+		c.names = append(c.names, name)
+	}
+	sort.Strings(c.names)
+
+	return c
+}
+
+func (c *metric) values(name string, values map[string]string) []string {
+	label := make([]string, len(c.names))
+	for i, n := range c.names {
+		label[i] = values[n]
+		delete(values, n)
+	}
+
+	for n := range values {
+		if !c.dropped[n] {
+			c.dropped[n] = true
+			log.Errorf("prometheus metric %s has no label %s, it was first recorded with %v", name, n, c.names)
+		}
+	}
+
+	return label
+}
+
+func sanitize(s string) string {
+	s = strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '_':
+			return r
+		default:
+			return '_'
+		}
+	}, s)
+
+	s = strings.Trim(s, "_")
+	if s != "" && s[0] >= '0' && s[0] <= '9' {
+		s = "_" + s
+	}
+
+	return s
+}
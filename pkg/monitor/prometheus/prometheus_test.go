@@ -0,0 +1,123 @@
+package prometheus
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/elastic-fray/pkg/monitor"
+)
+
+func scrape(t *testing.T, m Method) string {
+	t.Helper()
+
+	w := httptest.NewRecorder()
+	m.Handler().ServeHTTP(w, httptest.NewRequest("GET", "/metrics", nil))
+
+	body, err := ioutil.ReadAll(w.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return string(body)
+}
+
+func TestMetrics(t *testing.T) {
+	m := New(Config{
+		Namespace: "elastic-fray.",
+		Tags:      []string{"env:staging"},
+		Buckets:   []float64{1, 10},
+		Registry:  prometheus.NewRegistry(),
+	})
+
+	m.SetHistogram(time.Now(), "usecase.elastic.api.search", []string{"status:ok"})
+	m.SetHistogram(time.Now(), "usecase.elastic.api.search", []string{"status:ok"})
+	m.SetCount("usecase.elastic.api.search.error", []string{"status:error", "timeout"})
+
+	body := scrape(t, m)
+
+	for _, want := range []string{
+		`elastic_fray_usecase_elastic_api_search_seconds_bucket{client="",env="staging",error="",index="",operation="",outcome="",status="ok",status_code="",le="1"} 2`,
+		`elastic_fray_usecase_elastic_api_search_seconds_count{client="",env="staging",error="",index="",operation="",outcome="",status="ok",status_code=""} 2`,
+		`elastic_fray_usecase_elastic_api_search_error_total{client="",env="staging",error="",index="",operation="",outcome="",status="error",status_code="",timeout="true"} 1`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("metrics lack %s, got:\n%s", want, body)
+		}
+	}
+}
+
+func TestLabelsOfLaterCalls(t *testing.T) {
+	m := New(Config{
+		Registry: prometheus.NewRegistry(),
+	})
+
+	success := monitor.Call{Client: "api", Operation: "search", Index: "promo"}
+	failure := monitor.Call{Client: "officialclient", Operation: "search", Index: "promo", Status: 503, Err: errors.New("unavailable")}
+
+	m.SetHistogramDuration("search", time.Second, success.Tags())
+	m.SetHistogramDuration("search", time.Second, failure.Tags())
+	m.SetCount("requests", []string{"client:api", "retry:1"})
+	m.SetCount("requests", []string{"client:api"})
+
+	body := scrape(t, m)
+
+	for _, want := range []string{
+		`search_seconds_count{client="api",error="",index="promo",operation="search",outcome="success",status_code="unknown"} 1`,
+		`search_seconds_count{client="officialclient",error="other",index="promo",operation="search",outcome="error",status_code="503"} 1`,
+		`requests_total{client="api",error="",index="",operation="",outcome="",retry="",status_code=""} 1`,
+		`requests_total{client="api",error="",index="",operation="",outcome="",retry="1",status_code=""} 1`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("metrics lack %s, got:\n%s", want, body)
+		}
+	}
+}
+
+func TestSanitize(t *testing.T) {
+	for in, want := range map[string]string{
+		"elastic-fray.":   "elastic_fray",
+		"usecase.search":  "usecase_search",
+		"p99.9":           "p99_9",
+		"5xx":             "_5xx",
+		"already_fine_42": "already_fine_42",
+	} {
+		if got := sanitize(in); got != want {
+			t.Errorf("sanitize(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestValues(t *testing.T) {
+	m := New(Config{
+		Values:   []float64{10, 100},
+		Registry: prometheus.NewRegistry(),
+	})
+
+	m.SetHistogramDuration("search", 2*time.Second, nil)
+	m.SetHistogramValue("search.hits", 42, nil)
+	m.SetDistribution("search.hits", 4, nil)
+	m.SetGauge("workers", 8, []string{"client:api"})
+	m.SetError("search.error", context.DeadlineExceeded, nil)
+	m.SetError("search.error", nil, nil)
+
+	body := scrape(t, m)
+
+	for _, want := range []string{
+		`search_seconds_sum{client="",error="",index="",operation="",outcome="",status_code=""} 2`,
+		`search_hits_bucket{client="",error="",index="",operation="",outcome="",status_code="",le="10"} 1`,
+		`search_hits_bucket{client="",error="",index="",operation="",outcome="",status_code="",le="100"} 2`,
+		`workers{client="api",error="",index="",operation="",outcome="",status_code=""} 8`,
+		`search_error_total{client="",error="timeout",index="",operation="",outcome="",status_code=""} 1`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("metrics lack %s, got:\n%s", want, body)
+		}
+	}
+}
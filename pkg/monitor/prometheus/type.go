@@ -0,0 +1,44 @@
+package prometheus
+
+import (
+	"net/http"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/elastic-fray/pkg/monitor"
+)
+
+type (
+	Method interface {
+		monitor.Method
+		Handler() http.Handler
+	}
+)
+
+type (
+	Config struct {
+		Namespace string               // prefix of every metric name
+		Tags      []string             // "key:value" tags added to every metric, like the ones of the Datadog client
+		Buckets   []float64            // buckets of the duration histograms in seconds, prometheus.DefBuckets when empty
+		Values    []float64            // buckets of the value histograms and distributions, powers of 2 up to 2^20 when empty
+		Registry  *prometheus.Registry // a new one with the Go and process collectors when nil
+	}
+
+	Module struct {
+		config   Config
+		registry *prometheus.Registry
+		state    *state
+	}
+
+	state struct {
+		mutex   sync.Mutex
+		metrics map[string]*metric // by the name of the exposition
+	}
+
+	metric struct {
+		names     []string
+		dropped   map[string]bool      // names seen later that the metric cannot take
+		collector prometheus.Collector // nil when it could not be registered
+	}
+)
@@ -0,0 +1,61 @@
+package monitor
+
+import (
+	"context"
+	"strconv"
+	"sync/atomic"
+)
+
+type statusKey struct{}
+
+type Call struct {
+	Client    string
+	Operation string
+	Index     string // without the environment prefix, env is a tag of its own
+	Status    int    // HTTP status of the response, 0 when the client does not tell
+	Err       error
+}
+
+func (c Call) Tags() []string {
+	tags := make([]string, 0, 6)
+
+	for _, tag := range [][2]string{
+		{TagClient, c.Client},
+		{TagOperation, c.Operation},
+		{TagIndex, c.Index},
+	} {
+		if tag[1] != "" {
+			tags = append(tags, tag[0]+":"+tag[1])
+		}
+	}
+
+	status := StatusUnknown
+	if c.Status > 0 {
+		status = strconv.Itoa(c.Status)
+	}
+	tags = append(tags, TagStatus+":"+status)
+
+	if c.Err != nil {
+		return ErrorTags(c.Err, append(tags, TagOutcome+":"+OutcomeError))
+	}
+
+	return append(tags, TagOutcome+":"+OutcomeSuccess)
+}
+
+func WithStatus(ctx context.Context) context.Context {
+	return context.WithValue(ctx, statusKey{}, new(int32))
+}
+
+func SetStatus(ctx context.Context, status int) {
+	if s, ok := ctx.Value(statusKey{}).(*int32); ok {
+		atomic.StoreInt32(s, int32(status))
+	}
+}
+
+func Status(ctx context.Context) int {
+	if s, ok := ctx.Value(statusKey{}).(*int32); ok {
+		return int(atomic.LoadInt32(s))
+	}
+
+	return 0
+}
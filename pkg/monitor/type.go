@@ -6,16 +6,60 @@ import (
 	"github.com/ooyala/go-dogstatsd"
 )
 
+const (
+	BackendDatadog    = "datadog"
+	BackendPrometheus = "prometheus"
+	BackendMemory     = "memory"
+)
+
+const (
+	TagClient    = "client"
+	TagOperation = "operation"
+	TagIndex     = "index"
+	TagOutcome   = "outcome"
+	TagStatus    = "status_code"
+	TagError     = "error"
+
+	OutcomeSuccess = "success"
+	OutcomeError   = "error"
+
+	StatusUnknown = "unknown" // when the client does not tell, so every client sends the same tags
+)
+
+const (
+	ErrorTimeout    = "timeout"
+	ErrorCanceled   = "canceled"
+	ErrorConnection = "connection"
+	ErrorEOF        = "eof"
+	ErrorDecode     = "decode"
+	ErrorOther      = "other"
+)
+
 type (
 	Method interface {
 		SetHistogram(start time.Time, name string, tags []string)
+		SetHistogramDuration(name string, duration time.Duration, tags []string) // e.g. the took of a response
+		SetHistogramValue(name string, value float64, tags []string)             // e.g. the hits of a search
+		SetDistribution(name string, value float64, tags []string)               // a histogram aggregated across hosts
+		SetGauge(name string, value float64, tags []string)
 		SetCount(name string, tags []string)
+		SetError(name string, err error, tags []string) // counts err tagged error:<class>, nothing when nil
+	}
+
+	CountMethod interface {
+		AddCount(name string, n int64, tags []string)
 	}
 
 	DatadogMethod interface {
 		SetHistogram(start time.Time, name string, tags []string)
 		SetCount(name string, tags []string)
 	}
+
+	StatsdMethod interface {
+		Gauge(name string, value float64, tags []string, rate float64) error
+		Count(name string, value int64, tags []string, rate float64) error
+		Histogram(name string, value float64, tags []string, rate float64) error
+	}
 )
 
 type (
@@ -25,5 +69,6 @@ type (
 
 	Module struct {
 		datadog DatadogMethod
+		statsd  StatsdMethod
 	}
 )
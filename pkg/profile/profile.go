@@ -0,0 +1,58 @@
+package profile
+
+import (
+	"bytes"
+	"runtime"
+	"runtime/pprof"
+
+	"github.com/elastic-fray/entity/benchmark"
+)
+
+func New(c Config) Method {
+	return Module{
+		config: c,
+		state:  &state{},
+	}
+}
+
+func (m Module) Start() error {
+	runtime.GC()
+	runtime.ReadMemStats(&m.state.before)
+
+	// the allocs profile counts from the process start, the base lets
+	// pprof -base subtract what came before the window
+	if err := pprof.Lookup("allocs").WriteTo(&m.state.allocsBase, 0); err != nil {
+		return err
+	}
+
+	return pprof.StartCPUProfile(&m.state.cpu)
+}
+
+func (m Module) Stop() (benchmark.Profile, error) {
+	pprof.StopCPUProfile()
+
+	var after runtime.MemStats
+	runtime.ReadMemStats(&after)
+
+	p := benchmark.Profile{
+		Allocs:     after.Mallocs - m.state.before.Mallocs,
+		AllocBytes: after.TotalAlloc - m.state.before.TotalAlloc,
+		CPU:        m.state.cpu.Bytes(),
+		AllocsBase: m.state.allocsBase.Bytes(),
+	}
+
+	// the heap profile is as of the last GC
+	runtime.GC()
+
+	var heap, allocs bytes.Buffer
+	if err := pprof.Lookup("heap").WriteTo(&heap, 0); err != nil {
+		return p, err
+	}
+	if err := pprof.Lookup("allocs").WriteTo(&allocs, 0); err != nil {
+		return p, err
+	}
+
+	p.Heap, p.AllocsProfile = heap.Bytes(), allocs.Bytes()
+
+	return p, nil
+}
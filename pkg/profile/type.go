@@ -0,0 +1,30 @@
+package profile
+
+import (
+	"bytes"
+	"runtime"
+
+	"github.com/elastic-fray/entity/benchmark"
+)
+
+type (
+	Method interface {
+		Start() error
+		Stop() (benchmark.Profile, error)
+	}
+)
+
+type (
+	Config struct{}
+
+	Module struct {
+		config Config
+		state  *state
+	}
+
+	state struct {
+		cpu        bytes.Buffer
+		allocsBase bytes.Buffer
+		before     runtime.MemStats
+	}
+)
@@ -0,0 +1,97 @@
+package proxy
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+func New(c Config) Method {
+	if c.Transport == nil {
+		c.Transport = http.DefaultTransport
+	}
+
+	c.Target = strings.TrimSuffix(c.Target, "/")
+
+	return Module{
+		config: c,
+	}
+}
+
+func (m Module) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	out, err := http.NewRequest(r.Method, m.config.Target+r.URL.RequestURI(), r.Body)
+	if err != nil {
+		m.error(w, err)
+		return
+	}
+	out = out.WithContext(r.Context())
+	out.Header = r.Header.Clone()
+	out.ContentLength = r.ContentLength
+
+	if u, err := url.Parse(m.config.Target); err == nil && u.User != nil {
+		password, _ := u.User.Password()
+		out.SetBasicAuth(u.User.Username(), password)
+	}
+
+	resp, err := m.config.Transport.RoundTrip(out)
+	if err != nil {
+		if errors.Is(err, syscall.ECONNRESET) {
+			reset(w)
+			return
+		}
+
+		m.error(w, err)
+		return
+	}
+	defer resp.Body.Close()
+
+	for k, v := range resp.Header {
+		w.Header()[k] = v
+	}
+	if resp.ContentLength >= 0 {
+		w.Header().Set("Content-Length", strconv.FormatInt(resp.ContentLength, 10))
+	}
+	w.WriteHeader(resp.StatusCode)
+
+	if _, err := io.Copy(w, resp.Body); err != nil {
+		// sends what was read and drops the connection, the client reads
+		// an unexpected EOF
+		if flusher, ok := w.(http.Flusher); ok {
+			flusher.Flush()
+		}
+		panic(http.ErrAbortHandler)
+	}
+}
+
+func (m Module) error(w http.ResponseWriter, err error) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusBadGateway)
+
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"error":  map[string]interface{}{"type": "proxy_exception", "reason": err.Error()},
+		"status": http.StatusBadGateway,
+	})
+}
+
+func reset(w http.ResponseWriter) {
+	hijacker, ok := w.(http.Hijacker)
+	if !ok {
+		panic(http.ErrAbortHandler)
+	}
+
+	conn, _, err := hijacker.Hijack()
+	if err != nil {
+		panic(http.ErrAbortHandler)
+	}
+
+	if tcp, ok := conn.(*net.TCPConn); ok {
+		tcp.SetLinger(0)
+	}
+	conn.Close()
+}
@@ -0,0 +1,22 @@
+package proxy
+
+import (
+	"net/http"
+)
+
+type (
+	Method interface {
+		ServeHTTP(w http.ResponseWriter, r *http.Request)
+	}
+)
+
+type (
+	Config struct {
+		Target    string            // address requests are forwarded to, credentials are sent as basic auth
+		Transport http.RoundTripper // http.DefaultTransport when nil
+	}
+
+	Module struct {
+		config Config
+	}
+)
@@ -0,0 +1,207 @@
+package recorder
+
+import (
+	"math"
+	"math/bits"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/elastic-fray/entity/benchmark"
+)
+
+const (
+	subBucketBits = 8
+	subBuckets    = 1 << subBucketBits
+	halfBuckets   = subBuckets / 2
+	bucketCount   = subBuckets + (64-subBucketBits)*halfBuckets
+)
+
+func New() Method {
+	return Module{
+		mutex:      &sync.RWMutex{},
+		histograms: make(map[key]*histogram),
+		intervals:  make(map[key]*histogram),
+	}
+}
+
+func (m Module) Record(client, operation string, latency time.Duration, err error) {
+	k := key{
+		client:    client,
+		operation: operation,
+	}
+
+	m.mutex.RLock()
+	h, ok := m.histograms[k]
+	interval := m.intervals[k]
+	m.mutex.RUnlock()
+
+	if !ok {
+		m.mutex.Lock()
+		if h, ok = m.histograms[k]; !ok {
+			h = newHistogram()
+			m.histograms[k] = h
+			m.intervals[k] = newHistogram()
+		}
+		interval = m.intervals[k]
+		m.mutex.Unlock()
+	}
+
+	h.record(int64(latency), err != nil)
+	interval.record(int64(latency), err != nil)
+}
+
+func (m Module) Snapshot() []benchmark.Stats {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	return snapshot(m.histograms, false)
+}
+
+func (m Module) Interval() []benchmark.Stats {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	return snapshot(m.intervals, true)
+}
+
+func snapshot(histograms map[key]*histogram, reset bool) []benchmark.Stats {
+	stats := make([]benchmark.Stats, 0, len(histograms))
+
+	for k, h := range histograms {
+		s := h.stats(reset)
+		s.Client = k.client
+		s.Operation = k.operation
+
+		stats = append(stats, s)
+	}
+
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].Client != stats[j].Client {
+			return stats[i].Client < stats[j].Client
+		}
+
+		return stats[i].Operation < stats[j].Operation
+	})
+
+	return stats
+}
+
+func (m Module) Reset() {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	for k := range m.histograms {
+		delete(m.histograms, k)
+		delete(m.intervals, k)
+	}
+}
+
+func newHistogram() *histogram {
+	return &histogram{
+		counts: make([]int64, bucketCount),
+	}
+}
+
+func (h *histogram) record(value int64, failed bool) {
+	if value < 0 {
+		value = 0
+	}
+
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	h.counts[bucketIndex(value)]++
+
+	if h.count == 0 || value < h.min {
+		h.min = value
+	}
+	if value > h.max {
+		h.max = value
+	}
+
+	h.count++
+	h.sum += value
+
+	if failed {
+		h.errors++
+	}
+}
+
+func (h *histogram) stats(reset bool) benchmark.Stats {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	if reset {
+		defer h.reset()
+	}
+
+	s := benchmark.Stats{
+		Count:  h.count,
+		Errors: h.errors,
+		Min:    time.Duration(h.min),
+		Max:    time.Duration(h.max),
+	}
+
+	if h.count == 0 {
+		return s
+	}
+
+	s.Mean = time.Duration(h.sum / h.count)
+	s.P50 = h.percentile(50)
+	s.P90 = h.percentile(90)
+	s.P99 = h.percentile(99)
+	s.P999 = h.percentile(99.9)
+
+	return s
+}
+
+func (h *histogram) reset() {
+	for i := range h.counts {
+		h.counts[i] = 0
+	}
+
+	h.count, h.errors, h.sum, h.min, h.max = 0, 0, 0, 0, 0
+}
+
+func (h *histogram) percentile(p float64) time.Duration {
+	target := int64(math.Ceil(p / 100 * float64(h.count)))
+	if target < 1 {
+		target = 1
+	}
+
+	var seen int64
+
+	for i, count := range h.counts {
+		if seen += count; seen >= target {
+			value := bucketHighest(i)
+			if value > h.max {
+				value = h.max
+			}
+
+			return time.Duration(value)
+		}
+	}
+
+	return time.Duration(h.max)
+}
+
+func bucketIndex(value int64) int {
+	if value < subBuckets {
+		return int(value)
+	}
+
+	shift := bits.Len64(uint64(value)) - subBucketBits
+	return subBuckets + (shift-1)*halfBuckets + int(value>>uint(shift)) - halfBuckets
+}
+
+func bucketHighest(i int) int64 {
+	if i < subBuckets {
+		return int64(i)
+	}
+
+	shift := (i-subBuckets)/halfBuckets + 1
+	sub := int64((i-subBuckets)%halfBuckets + halfBuckets)
+
+	return (sub+1)<<uint(shift) - 1
+}
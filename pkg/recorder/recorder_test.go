@@ -0,0 +1,104 @@
+package recorder
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestInterval(t *testing.T) {
+	m := New()
+
+	m.Record("api", "search", time.Millisecond, nil)
+	m.Record("api", "search", 3*time.Millisecond, nil)
+
+	if got := m.Interval(); len(got) != 1 || got[0].Count != 2 || got[0].Max != 3*time.Millisecond {
+		t.Fatalf("first interval = %+v, want 2 requests up to 3ms", got)
+	}
+
+	m.Record("api", "search", 2*time.Millisecond, nil)
+
+	got := m.Interval()
+	if len(got) != 1 || got[0].Count != 1 || got[0].Min != 2*time.Millisecond || got[0].Max != 2*time.Millisecond {
+		t.Errorf("second interval = %+v, want only the 2ms request", got)
+	}
+
+	if got := m.Interval(); got[0].Count != 0 {
+		t.Errorf("empty interval = %+v, want no requests", got)
+	}
+
+	if total := m.Snapshot(); total[0].Count != 3 || total[0].Min != time.Millisecond {
+		t.Errorf("snapshot = %+v, want all 3 requests", total)
+	}
+}
+
+func TestBuckets(t *testing.T) {
+	previous := -1
+
+	for _, value := range []int64{0, 1, 255, 256, 257, 511, 512, 1000, 123456, int64(time.Second), int64(time.Hour), math.MaxInt64} {
+		i := bucketIndex(value)
+		if i < previous || i >= bucketCount {
+			t.Errorf("bucket of %d = %d, want from %d and below %d", value, i, previous, bucketCount)
+		}
+		previous = i
+
+		highest := bucketHighest(i)
+		if highest < value || float64(highest-value) > float64(value)/100 {
+			t.Errorf("highest of the bucket of %d = %d, want within 1%%", value, highest)
+		}
+	}
+}
+
+func TestPercentiles(t *testing.T) {
+	uniform := make([]time.Duration, 0, 1000)
+	for i := 1; i <= 1000; i++ {
+		uniform = append(uniform, time.Duration(i)*time.Millisecond)
+	}
+
+	constant := make([]time.Duration, 0, 100)
+	for i := 0; i < 100; i++ {
+		constant = append(constant, 42*time.Millisecond)
+	}
+
+	var bimodal []time.Duration
+	for i := 0; i < 1000; i++ {
+		latency := time.Millisecond
+		if i%100 >= 95 {
+			latency = 200 * time.Millisecond
+		}
+		bimodal = append(bimodal, latency)
+	}
+
+	for name, c := range map[string]struct {
+		latencies          []time.Duration
+		p50, p90, p99, max time.Duration
+	}{
+		"uniform":  {uniform, 500 * time.Millisecond, 900 * time.Millisecond, 990 * time.Millisecond, time.Second},
+		"constant": {constant, 42 * time.Millisecond, 42 * time.Millisecond, 42 * time.Millisecond, 42 * time.Millisecond},
+		"bimodal":  {bimodal, time.Millisecond, time.Millisecond, 200 * time.Millisecond, 200 * time.Millisecond},
+	} {
+		m := New()
+		for _, latency := range c.latencies {
+			m.Record("api", "search", latency, nil)
+		}
+
+		s := m.Snapshot()[0]
+
+		for _, p := range []struct {
+			name      string
+			got, want time.Duration
+		}{
+			{"p50", s.P50, c.p50},
+			{"p90", s.P90, c.p90},
+			{"p99", s.P99, c.p99},
+		} {
+			if p.got < p.want || p.got > p.want+p.want/100 {
+				t.Errorf("%s %s = %v, want %v within 1%%", name, p.name, p.got, p.want)
+			}
+		}
+
+		if s.Max != c.max {
+			t.Errorf("%s max = %v, want %v", name, s.Max, c.max)
+		}
+	}
+}
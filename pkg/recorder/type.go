@@ -0,0 +1,40 @@
+package recorder
+
+import (
+	"sync"
+	"time"
+
+	"github.com/elastic-fray/entity/benchmark"
+)
+
+type (
+	Method interface {
+		Record(client, operation string, latency time.Duration, err error)
+		Snapshot() []benchmark.Stats
+		Interval() []benchmark.Stats // since the previous call, for live views
+		Reset()
+	}
+)
+
+type (
+	Module struct {
+		mutex      *sync.RWMutex
+		histograms map[key]*histogram
+		intervals  map[key]*histogram
+	}
+
+	key struct {
+		client    string
+		operation string
+	}
+
+	histogram struct {
+		mutex  sync.Mutex
+		counts []int64
+		count  int64
+		errors int64
+		sum    int64
+		min    int64
+		max    int64
+	}
+)
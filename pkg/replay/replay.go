@@ -0,0 +1,247 @@
+package replay
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/elastic-fray/pkg/proxy"
+)
+
+func New(c Config) (Method, error) {
+	if c.Transport == nil {
+		c.Transport = http.DefaultTransport
+	}
+
+	m := Module{
+		config:   c,
+		cassette: &cassette{},
+	}
+
+	switch c.Mode {
+	case ModeRecord:
+	case ModeReplay:
+		data, err := ioutil.ReadFile(c.File)
+		if err != nil {
+			return nil, err
+		}
+
+		if err = json.Unmarshal(data, m.cassette); err != nil {
+			return nil, fmt.Errorf("%s: %v", c.File, err)
+		}
+	default:
+		return nil, fmt.Errorf("unknown replay mode %q, expected %s or %s", c.Mode, ModeRecord, ModeReplay)
+	}
+
+	m.cassette.index()
+
+	return m, nil
+}
+
+func (m Module) RoundTrip(r *http.Request) (*http.Response, error) {
+	var body []byte
+
+	if r.Body != nil {
+		var err error
+		if body, err = ioutil.ReadAll(r.Body); err != nil {
+			return nil, err
+		}
+		r.Body.Close()
+		r.Body = ioutil.NopCloser(bytes.NewReader(body))
+	}
+
+	req := request{
+		Method: r.Method,
+		URL:    requestURL(r.URL),
+		Body:   string(body),
+	}
+
+	if m.config.Mode == ModeReplay {
+		recorded, ok := m.cassette.find(req)
+		if !ok {
+			return nil, fmt.Errorf("replay: no recorded response for %s %s", req.Method, req.URL)
+		}
+
+		return recorded.http(r), nil
+	}
+
+	resp, err := m.config.Transport.RoundTrip(r)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = ioutil.NopCloser(bytes.NewReader(data))
+
+	header := map[string][]string{}
+	for k, v := range resp.Header {
+		switch k {
+		case "Date", "Content-Length":
+		default:
+			header[k] = v
+		}
+	}
+
+	m.cassette.add(interaction{
+		Request: req,
+		Response: response{
+			Status: resp.StatusCode,
+			Header: header,
+			Body:   string(data),
+		},
+	})
+
+	return resp, nil
+}
+
+func (m Module) Handler(target string) http.Handler {
+	if target == "" {
+		target = "http://replay"
+	}
+
+	return proxy.New(proxy.Config{
+		Target:    target,
+		Transport: m,
+	})
+}
+
+func (m Module) Save() error {
+	if m.config.Mode != ModeRecord {
+		return nil
+	}
+
+	m.cassette.mutex.Lock()
+	data, err := json.MarshalIndent(m.cassette, "", "  ")
+	m.cassette.mutex.Unlock()
+	if err != nil {
+		return err
+	}
+
+	if err = os.MkdirAll(filepath.Dir(m.config.File), 0755); err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(m.config.File, data, 0644)
+}
+
+func (c *cassette) index() {
+	c.served = make([]bool, len(c.Interactions))
+	c.exact = make(map[string][]int)
+	c.loose = make(map[string][]int)
+	c.next = make(map[string]int)
+
+	for i, recorded := range c.Interactions {
+		exact, loose := keys(recorded.Request)
+		c.exact[exact] = append(c.exact[exact], i)
+		c.loose[loose] = append(c.loose[loose], i)
+	}
+}
+
+func (c *cassette) add(recorded interaction) {
+	exact, _ := keys(recorded.Request)
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if _, ok := c.exact[exact]; ok {
+		return
+	}
+
+	c.exact[exact] = []int{len(c.Interactions)}
+	c.Interactions = append(c.Interactions, recorded)
+}
+
+func (c *cassette) find(req request) (response, bool) {
+	exact, loose := keys(req)
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if recorded, ok := c.serve(exact, c.exact[exact]); ok {
+		return recorded, true
+	}
+
+	return c.serve(loose, c.loose[loose])
+}
+
+func (c *cassette) serve(key string, list []int) (response, bool) {
+	if len(list) == 0 {
+		return response{}, false
+	}
+
+	i := c.next[key]
+	for i < len(list) && c.served[list[i]] {
+		i++
+	}
+	c.next[key] = i
+
+	if i == len(list) {
+		return c.Interactions[list[i-1]].Response, true
+	}
+
+	c.served[list[i]] = true
+
+	return c.Interactions[list[i]].Response, true
+}
+
+func keys(req request) (string, string) {
+	loose := req.Method + " " + req.URL
+
+	return loose + "\n" + canonical(req.Body), loose
+}
+
+func (r response) http(req *http.Request) *http.Response {
+	header := http.Header{}
+	for k, v := range r.Header {
+		header[k] = v
+	}
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", r.Status, http.StatusText(r.Status)),
+		StatusCode:    r.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          ioutil.NopCloser(strings.NewReader(r.Body)),
+		ContentLength: int64(len(r.Body)),
+		Request:       req,
+	}
+}
+
+func requestURL(u *url.URL) string {
+	if u.RawQuery == "" {
+		return u.EscapedPath()
+	}
+
+	return u.EscapedPath() + "?" + u.Query().Encode()
+}
+
+func canonical(body string) string {
+	var lines []string
+
+	for _, line := range strings.Split(strings.TrimSpace(body), "\n") {
+		var v interface{}
+		if err := json.Unmarshal([]byte(line), &v); err != nil {
+			return body
+		}
+
+		data, err := json.Marshal(v)
+		if err != nil {
+			return body
+		}
+		lines = append(lines, string(data))
+	}
+
+	return strings.Join(lines, "\n")
+}
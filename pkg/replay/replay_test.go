@@ -0,0 +1,178 @@
+package replay
+
+import (
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/elastic/go-elasticsearch/v7"
+
+	"github.com/elastic-fray/pkg/elastic/fake"
+)
+
+func TestRecordReplay(t *testing.T) {
+	dir, err := ioutil.TempDir("", "replay")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	file := filepath.Join(dir, "fixtures", "officialclient.json")
+
+	cluster := fake.New(fake.Config{})
+	for id, source := range map[string]string{
+		"1": `{"order_id":1,"source":"marketplace"}`,
+		"2": `{"order_id":2,"source":"digital"}`,
+	} {
+		if err := cluster.Put("promo-order-usage", id, source); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	recorder, err := New(Config{Mode: ModeRecord, File: file})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	recorded := exchange(t, cluster.URL(), recorder)
+
+	if err = recorder.Save(); err != nil {
+		t.Fatal(err)
+	}
+	cluster.Close()
+
+	replayer, err := New(Config{Mode: ModeReplay, File: file})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the cluster is gone, every answer comes from the fixtures
+	replayed := exchange(t, "http://127.0.0.1:1", replayer)
+
+	for i := range recorded {
+		if replayed[i] != recorded[i] {
+			t.Errorf("response %d replayed as %s, recorded %s", i, replayed[i], recorded[i])
+		}
+	}
+
+	if _, err := replayer.RoundTrip(httptest.NewRequest(http.MethodGet, "/unknown", nil)); err == nil {
+		t.Error("replaying an unrecorded request succeeded")
+	}
+}
+
+func exchange(t *testing.T, address string, transport Method) []string {
+	client, err := elasticsearch.NewClient(elasticsearch.Config{
+		Addresses: []string{address},
+		Transport: transport,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var bodies []string
+
+	resp, err := client.Search(
+		client.Search.WithIndex("promo-order-usage"),
+		client.Search.WithBody(strings.NewReader(`{"query":{"query_string":{"query":"source:marketplace"}}}`)),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bodies = append(bodies, read(t, resp.Body))
+
+	proxy := httptest.NewServer(transport.Handler(address))
+	defer proxy.Close()
+
+	// same body with another key order and spacing
+	count, err := http.Post(proxy.URL+"/promo-order-usage/_count", "application/json",
+		strings.NewReader(`{ "query": { "query_string": { "query": "source:digital" } } }`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count.StatusCode != http.StatusOK {
+		t.Fatalf("count through the proxy: %s", count.Status)
+	}
+	bodies = append(bodies, read(t, count.Body))
+
+	return bodies
+}
+
+func read(t *testing.T, body io.ReadCloser) string {
+	defer body.Close()
+
+	data, err := ioutil.ReadAll(body)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return string(data)
+}
+
+func TestRecordOnce(t *testing.T) {
+	dir, err := ioutil.TempDir("", "replay")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	file := filepath.Join(dir, "nethttp.json")
+
+	var hits int
+	cluster := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+		io.WriteString(w, `{"count":`+strconv.Itoa(hits)+`}`)
+	}))
+	defer cluster.Close()
+
+	recorder, err := New(Config{Mode: ModeRecord, File: file})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	client := &http.Client{Transport: recorder}
+	for _, body := range []string{`{"a":1,"b":2}`, `{ "b": 2, "a": 1 }`, `{"a":2}`} {
+		resp, err := client.Post(cluster.URL+"/promo-order-usage/_count", "application/json", strings.NewReader(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		read(t, resp.Body)
+	}
+
+	if err = recorder.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	replayer, err := New(Config{Mode: ModeReplay, File: file})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if n := len(replayer.(Module).cassette.Interactions); n != 2 {
+		t.Errorf("recorded %d interactions, want 2", n)
+	}
+
+	client = &http.Client{Transport: replayer}
+	for _, c := range []struct {
+		body string
+		want string
+	}{
+		{`{"b":2,"a":1}`, `{"count":1}`},
+		{`{"a":1,"b":2}`, `{"count":1}`},
+		{`{"a":2}`, `{"count":3}`},
+		{`{"a":3}`, `{"count":3}`}, // same url, the last one keeps answering
+	} {
+		resp, err := client.Post("http://127.0.0.1:1/promo-order-usage/_count", "application/json", strings.NewReader(c.body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := read(t, resp.Body); got != c.want {
+			t.Errorf("%s replayed %s, want %s", c.body, got, c.want)
+		}
+	}
+}
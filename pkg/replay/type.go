@@ -0,0 +1,58 @@
+package replay
+
+import (
+	"net/http"
+	"sync"
+)
+
+const (
+	ModeRecord = "record"
+	ModeReplay = "replay"
+)
+
+type (
+	Method interface {
+		RoundTrip(r *http.Request) (*http.Response, error)
+		Handler(target string) http.Handler
+		Save() error
+	}
+)
+
+type (
+	Config struct {
+		Mode      string
+		File      string
+		Transport http.RoundTripper // where recording sends requests, http.DefaultTransport when nil
+	}
+
+	Module struct {
+		config   Config
+		cassette *cassette
+	}
+
+	cassette struct {
+		mutex        sync.Mutex
+		Interactions []interaction `json:"interactions"`
+		served       []bool
+		exact        map[string][]int // interactions by method, url and canonical body
+		loose        map[string][]int // by method and url
+		next         map[string]int   // first interaction of a list that may be unserved
+	}
+
+	interaction struct {
+		Request  request  `json:"request"`
+		Response response `json:"response"`
+	}
+
+	request struct {
+		Method string `json:"method"`
+		URL    string `json:"url"`
+		Body   string `json:"body,omitempty"`
+	}
+
+	response struct {
+		Status int                 `json:"status"`
+		Header map[string][]string `json:"header,omitempty"`
+		Body   string              `json:"body,omitempty"`
+	}
+)
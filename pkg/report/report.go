@@ -0,0 +1,380 @@
+package report
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/elastic-fray/entity/benchmark"
+	"github.com/elastic-fray/pkg/fault"
+)
+
+func New(c Config) Method {
+	return Module{
+		config: c,
+	}
+}
+
+func (m Module) Build(result benchmark.Result) (Report, error) {
+	report := Report{
+		Scenario:    result.Scenario,
+		GeneratedAt: time.Now(),
+		Baseline:    m.config.Baseline,
+		Clients:     result.Clients,
+		OpenLoop:    result.OpenLoop,
+		Faults:      result.Faults,
+		Injected:    result.Injected,
+		Profiles:    result.Profiles,
+	}
+
+	index := make(map[string]int)
+
+	for _, s := range result.Stats {
+		if !contains(report.Clients, s.Client) {
+			report.Clients = append(report.Clients, s.Client)
+		}
+
+		i, ok := index[s.Operation]
+		if !ok {
+			i = len(report.Operations)
+			index[s.Operation] = i
+			report.Operations = append(report.Operations, Operation{
+				Operation: s.Operation,
+			})
+		}
+
+		report.Operations[i].Clients = append(report.Operations[i].Clients, client(s))
+	}
+
+	if report.Baseline == "" && len(report.Clients) > 0 {
+		report.Baseline = report.Clients[0]
+	}
+
+	if len(report.Clients) > 0 && !contains(report.Clients, report.Baseline) {
+		return report, fmt.Errorf("baseline %s did not run, the clients were %s", report.Baseline, strings.Join(report.Clients, ", "))
+	}
+
+	for i := range report.Operations {
+		var baseline *Client
+
+		for j := range report.Operations[i].Clients {
+			if report.Operations[i].Clients[j].Client == report.Baseline {
+				baseline = &report.Operations[i].Clients[j]
+			}
+		}
+
+		if baseline == nil {
+			continue
+		}
+
+		for j := range report.Operations[i].Clients {
+			c := &report.Operations[i].Clients[j]
+			if c.Client != report.Baseline {
+				c.Difference = difference(*baseline, *c)
+			}
+		}
+	}
+
+	return report, nil
+}
+
+func (m Module) Write(w io.Writer, format string, report Report) error {
+	switch format {
+	case FormatMarkdown:
+		return writeMarkdown(w, report)
+	case FormatJSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(report)
+	case FormatCSV:
+		return writeCSV(w, report)
+	}
+
+	return fmt.Errorf("unknown report format: %s", format)
+}
+
+func (m Module) WriteFile(path string, report Report) error {
+	var format string
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".md", ".markdown":
+		format = FormatMarkdown
+	case ".json":
+		format = FormatJSON
+	case ".csv":
+		format = FormatCSV
+	default:
+		return fmt.Errorf("unknown report format for %s, use .md, .json or .csv", path)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	if err := m.Write(f, format, report); err != nil {
+		f.Close()
+		return err
+	}
+
+	return f.Close()
+}
+
+func client(s benchmark.Stats) Client {
+	c := Client{
+		Client:     s.Client,
+		Count:      s.Count,
+		Errors:     s.Errors,
+		Throughput: s.Throughput(),
+		Latency: Latency{
+			Mean: milliseconds(s.Mean),
+			P50:  milliseconds(s.P50),
+			P90:  milliseconds(s.P90),
+			P99:  milliseconds(s.P99),
+			P999: milliseconds(s.P999),
+			Max:  milliseconds(s.Max),
+		},
+	}
+
+	if s.Count > 0 {
+		c.ErrorRate = float64(s.Errors) / float64(s.Count)
+	}
+
+	return c
+}
+
+func difference(baseline, c Client) *Difference {
+	return &Difference{
+		Throughput: relative(baseline.Throughput, c.Throughput),
+		ErrorRate:  (c.ErrorRate - baseline.ErrorRate) * 100,
+		Latency: LatencyDifference{
+			Mean: relative(baseline.Latency.Mean, c.Latency.Mean),
+			P50:  relative(baseline.Latency.P50, c.Latency.P50),
+			P90:  relative(baseline.Latency.P90, c.Latency.P90),
+			P99:  relative(baseline.Latency.P99, c.Latency.P99),
+			P999: relative(baseline.Latency.P999, c.Latency.P999),
+			Max:  relative(baseline.Latency.Max, c.Latency.Max),
+		},
+	}
+}
+
+func relative(baseline, value float64) *float64 {
+	var d float64
+
+	if baseline != 0 {
+		d = (value - baseline) / baseline * 100
+	} else if value != 0 {
+		return nil
+	}
+
+	return &d
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+
+	return false
+}
+
+func milliseconds(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
+
+func writeMarkdown(w io.Writer, report Report) error {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "# %s: %s\n\n", strings.Join(report.Clients, " vs "), report.Scenario)
+	fmt.Fprintf(&b, "Generated at %s, differences are relative to `%s`.\n", report.GeneratedAt.Format(time.RFC3339), report.Baseline)
+
+	if report.OpenLoop {
+		fmt.Fprintf(&b, "\nOpen loop: requests went out on a fixed schedule, latencies count from when each was due.\n")
+	}
+
+	if report.Faults != nil {
+		fmt.Fprintf(&b, "\nFaults: %s.\n", DescribeFaults(*report.Faults))
+		writeInjected(&b, report.Injected)
+	}
+
+	for _, o := range report.Operations {
+		fmt.Fprintf(&b, "\n## %s\n\n", o.Operation)
+		fmt.Fprintf(&b, "| Client | Count | Errors | Error rate | Req/s | Mean (ms) | p50 (ms) | p90 (ms) | p99 (ms) | p99.9 (ms) | Max (ms) |\n")
+		fmt.Fprintf(&b, "|---|---:|---:|---:|---:|---:|---:|---:|---:|---:|---:|\n")
+
+		for _, c := range o.Clients {
+			errorRate := fmt.Sprintf("%.2f%%", c.ErrorRate*100)
+			cells := []string{
+				fmt.Sprintf("%.1f", c.Throughput),
+				fmt.Sprintf("%.3f", c.Latency.Mean),
+				fmt.Sprintf("%.3f", c.Latency.P50),
+				fmt.Sprintf("%.3f", c.Latency.P90),
+				fmt.Sprintf("%.3f", c.Latency.P99),
+				fmt.Sprintf("%.3f", c.Latency.P999),
+				fmt.Sprintf("%.3f", c.Latency.Max),
+			}
+
+			if d := c.Difference; d != nil {
+				errorRate += fmt.Sprintf(" (%+.1fpp)", d.ErrorRate)
+
+				for i, change := range d.changes() {
+					cells[i] += " (" + percentChange(change) + ")"
+				}
+			}
+
+			fmt.Fprintf(&b, "| %s | %d | %d | %s | %s |\n", c.Client, c.Count, c.Errors, errorRate, strings.Join(cells, " | "))
+		}
+	}
+
+	if len(report.Profiles) > 0 {
+		fmt.Fprintf(&b, "\n## allocations\n\n")
+		fmt.Fprintf(&b, "Allocations in this process per request over the measurement window, the harness included.\n\n")
+		fmt.Fprintf(&b, "| Client | Requests | Allocs/op | Bytes/op | Profiles |\n")
+		fmt.Fprintf(&b, "|---|---:|---:|---:|---|\n")
+
+		for _, p := range report.Profiles {
+			fmt.Fprintf(&b, "| %s | %d | %.0f | %.0f | %s |\n", p.Client, p.Requests, p.AllocsPerOp, p.BytesPerOp, strings.Join(p.Files, ", "))
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func writeInjected(b *strings.Builder, injected map[string]map[string]int64) {
+	if len(injected) == 0 {
+		return
+	}
+
+	clients := make([]string, 0, len(injected))
+	for client := range injected {
+		clients = append(clients, client)
+	}
+	sort.Strings(clients)
+
+	fmt.Fprintf(b, "\n| Client | Delayed | 429 | 503 | Truncated | Reset |\n")
+	fmt.Fprintf(b, "|---|---:|---:|---:|---:|---:|\n")
+
+	for _, client := range clients {
+		i := injected[client]
+		fmt.Fprintf(b, "| %s | %d | %d | %d | %d | %d |\n", client,
+			i[fault.KindLatency], i[fault.KindTooManyRequests], i[fault.KindUnavailable], i[fault.KindTruncate], i[fault.KindReset])
+	}
+}
+
+func DescribeFaults(f benchmark.Faults) string {
+	var parts []string
+
+	if f.Latency > 0 || f.Jitter > 0 {
+		latency := f.Latency.String() + " latency"
+		if f.Jitter > 0 {
+			latency += " plus up to " + f.Jitter.String() + " jitter"
+		}
+		if f.LatencyRate > 0 {
+			latency += fmt.Sprintf(" on %s of requests", percent(f.LatencyRate))
+		}
+		parts = append(parts, latency)
+	}
+
+	for _, fault := range []struct {
+		rate  float64
+		label string
+	}{
+		{f.TooManyRequests, "429"},
+		{f.Unavailable, "503"},
+		{f.Truncate, "truncated bodies"},
+		{f.Reset, "connection resets"},
+	} {
+		if fault.rate > 0 {
+			parts = append(parts, percent(fault.rate)+" "+fault.label)
+		}
+	}
+
+	if len(parts) == 0 {
+		return "none"
+	}
+
+	return strings.Join(parts, ", ")
+}
+
+func percent(rate float64) string {
+	return strconv.FormatFloat(rate*100, 'f', -1, 64) + "%"
+}
+
+func (d Difference) changes() []*float64 {
+	return []*float64{
+		d.Throughput,
+		d.Latency.Mean, d.Latency.P50, d.Latency.P90, d.Latency.P99, d.Latency.P999, d.Latency.Max,
+	}
+}
+
+func percentChange(change *float64) string {
+	if change == nil {
+		return "n/a"
+	}
+
+	return fmt.Sprintf("%+.1f%%", *change)
+}
+
+func writeCSV(w io.Writer, report Report) error {
+	writer := csv.NewWriter(w)
+
+	if err := writer.Write([]string{
+		"operation", "client", "count", "errors", "error_rate", "throughput",
+		"mean_ms", "p50_ms", "p90_ms", "p99_ms", "p999_ms", "max_ms",
+		"error_rate_diff_pp", "throughput_diff_pct",
+		"mean_diff_pct", "p50_diff_pct", "p90_diff_pct", "p99_diff_pct", "p999_diff_pct", "max_diff_pct",
+	}); err != nil {
+		return err
+	}
+
+	for _, o := range report.Operations {
+		for _, c := range o.Clients {
+			record := []string{
+				o.Operation, c.Client,
+				strconv.FormatInt(c.Count, 10), strconv.FormatInt(c.Errors, 10),
+				float(c.ErrorRate), float(c.Throughput),
+				float(c.Latency.Mean), float(c.Latency.P50), float(c.Latency.P90),
+				float(c.Latency.P99), float(c.Latency.P999), float(c.Latency.Max),
+			}
+
+			if d := c.Difference; d != nil {
+				record = append(record, float(d.ErrorRate))
+
+				for _, change := range d.changes() {
+					if change == nil {
+						record = append(record, "n/a")
+					} else {
+						record = append(record, float(*change))
+					}
+				}
+			} else {
+				record = append(record, "", "", "", "", "", "", "", "")
+			}
+
+			if err := writer.Write(record); err != nil {
+				return err
+			}
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+func float(f float64) string {
+	return strconv.FormatFloat(f, 'f', 4, 64)
+}
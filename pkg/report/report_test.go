@@ -0,0 +1,133 @@
+package report
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/elastic-fray/entity/benchmark"
+)
+
+var result = benchmark.Result{
+	Scenario: "sick-cluster",
+	Clients:  []string{"officialclient", "api"},
+	Stats: []benchmark.Stats{
+		{
+			Client:    "api",
+			Operation: "search",
+			Count:     100,
+			Errors:    50,
+			Elapsed:   10 * time.Second,
+			Mean:      30 * time.Millisecond,
+			P50:       20 * time.Millisecond,
+			P90:       40 * time.Millisecond,
+			P99:       80 * time.Millisecond,
+			P999:      90 * time.Millisecond,
+			Max:       100 * time.Millisecond,
+		},
+		{
+			Client:    "officialclient",
+			Operation: "search",
+			Count:     100,
+			Elapsed:   10 * time.Second,
+			Mean:      20 * time.Millisecond,
+			P50:       20 * time.Millisecond,
+			P90:       40 * time.Millisecond,
+			P99:       40 * time.Millisecond,
+			P999:      50 * time.Millisecond,
+		},
+	},
+}
+
+func TestBuild(t *testing.T) {
+	report, err := New(Config{}).Build(result)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if report.Baseline != "officialclient" {
+		t.Errorf("baseline = %s, want the first client that ran", report.Baseline)
+	}
+
+	if len(report.Operations) != 1 || len(report.Operations[0].Clients) != 2 {
+		t.Fatalf("operations = %+v, want search of both clients", report.Operations)
+	}
+
+	api := report.Operations[0].Clients[0]
+	d := api.Difference
+	if api.Client != "api" || d == nil {
+		t.Fatalf("api = %+v, want a difference", api)
+	}
+
+	if d.ErrorRate != 50 {
+		t.Errorf("error rate difference = %g, want 50 points", d.ErrorRate)
+	}
+	if d.Latency.Mean == nil || *d.Latency.Mean != 50 {
+		t.Errorf("mean difference = %v, want 50%%", d.Latency.Mean)
+	}
+	if d.Latency.P50 == nil || *d.Latency.P50 != 0 {
+		t.Errorf("p50 difference = %v, want 0%%", d.Latency.P50)
+	}
+	if d.Latency.Max != nil {
+		t.Errorf("max difference = %g, want none from a baseline of 0", *d.Latency.Max)
+	}
+
+	if report.Operations[0].Clients[1].Difference != nil {
+		t.Error("the baseline has a difference")
+	}
+
+	if report, _ = New(Config{Baseline: "api"}).Build(result); report.Baseline != "api" {
+		t.Errorf("baseline = %s, want the configured api", report.Baseline)
+	}
+
+	if _, err = New(Config{Baseline: "nethttp"}).Build(result); err == nil {
+		t.Error("built a report against nethttp, which did not run")
+	}
+}
+
+func TestWrite(t *testing.T) {
+	m := New(Config{})
+	report, err := m.Build(result)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for format, want := range map[string][]string{
+		FormatMarkdown: {
+			"# officialclient vs api: sick-cluster",
+			"| api | 100 | 50 | 50.00% (+50.0pp) | 10.0 (+0.0%) | 30.000 (+50.0%) | 20.000 (+0.0%) | 40.000 (+0.0%) | 80.000 (+100.0%) | 90.000 (+80.0%) | 100.000 (n/a) |",
+			"| officialclient | 100 | 0 | 0.00% | 10.0 | 20.000 |",
+		},
+		FormatCSV: {
+			"search,api,100,50,0.5000,10.0000,30.0000,20.0000,40.0000,80.0000,90.0000,100.0000,50.0000,0.0000,50.0000,0.0000,0.0000,100.0000,80.0000,n/a",
+			"search,officialclient,100,0,0.0000,10.0000,20.0000,20.0000,40.0000,40.0000,50.0000,0.0000,,,,,,,,",
+		},
+		FormatJSON: {
+			`"error_rate_points": 50`,
+			`"max": null`,
+		},
+	} {
+		var b strings.Builder
+		if err := m.Write(&b, format, report); err != nil {
+			t.Fatal(err)
+		}
+
+		for _, line := range want {
+			if !strings.Contains(b.String(), line) {
+				t.Errorf("%s report lacks %q, got:\n%s", format, line, b.String())
+			}
+		}
+
+		if format == FormatJSON {
+			var decoded Report
+			if err := json.Unmarshal([]byte(b.String()), &decoded); err != nil {
+				t.Error(err)
+			}
+		}
+	}
+
+	if err := m.WriteFile("report.txt", report); err == nil {
+		t.Error("wrote a report without a known extension")
+	}
+}
@@ -0,0 +1,83 @@
+package report
+
+import (
+	"io"
+	"time"
+
+	"github.com/elastic-fray/entity/benchmark"
+)
+
+const (
+	FormatMarkdown = "markdown"
+	FormatJSON     = "json"
+	FormatCSV      = "csv"
+)
+
+type (
+	Method interface {
+		Build(result benchmark.Result) (Report, error)
+		Write(w io.Writer, format string, report Report) error
+		WriteFile(path string, report Report) error
+	}
+)
+
+type (
+	Config struct {
+		Baseline string // client the others are compared against, defaults to the first one that ran
+	}
+
+	Module struct {
+		config Config
+	}
+
+	Report struct {
+		Scenario    string                      `json:"scenario"`
+		GeneratedAt time.Time                   `json:"generated_at"`
+		Baseline    string                      `json:"baseline"`
+		Clients     []string                    `json:"clients"`
+		OpenLoop    bool                        `json:"open_loop,omitempty"`
+		Faults      *benchmark.Faults           `json:"faults,omitempty"`
+		Injected    map[string]map[string]int64 `json:"injected,omitempty"`
+		Operations  []Operation                 `json:"operations"`
+		Profiles    []benchmark.Profile         `json:"profiles,omitempty"`
+	}
+
+	Operation struct {
+		Operation string   `json:"operation"`
+		Clients   []Client `json:"clients"`
+	}
+
+	Client struct {
+		Client     string      `json:"client"`
+		Count      int64       `json:"count"`
+		Errors     int64       `json:"errors"`
+		ErrorRate  float64     `json:"error_rate"`
+		Throughput float64     `json:"throughput"`
+		Latency    Latency     `json:"latency_ms"`
+		Difference *Difference `json:"difference_pct,omitempty"`
+	}
+
+	Latency struct {
+		Mean float64 `json:"mean"`
+		P50  float64 `json:"p50"`
+		P90  float64 `json:"p90"`
+		P99  float64 `json:"p99"`
+		P999 float64 `json:"p999"`
+		Max  float64 `json:"max"`
+	}
+
+	Difference struct {
+		Throughput *float64          `json:"throughput"`
+		ErrorRate  float64           `json:"error_rate_points"`
+		Latency    LatencyDifference `json:"latency"`
+	}
+
+	LatencyDifference struct {
+		Mean *float64 `json:"mean"`
+		P50  *float64 `json:"p50"`
+		P90  *float64 `json:"p90"`
+		P99  *float64 `json:"p99"`
+		P999 *float64 `json:"p999"`
+		Max  *float64 `json:"max"`
+	}
+)
@@ -0,0 +1,238 @@
+package scenario
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/elastic-fray/entity/benchmark"
+	elasticEntity "github.com/elastic-fray/entity/elastic"
+	"github.com/elastic-fray/pkg/tracker"
+)
+
+func New(c Config) Method {
+	if len(c.Clients) == 0 {
+		c.Clients = benchmark.Clients
+	}
+
+	return Module{
+		config: c,
+	}
+}
+
+func (m Module) Load(path string) ([]benchmark.Parameter, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		file  benchmark.Scenarios
+		set   setScenarios // which of the fields whose zero value is a setting the file sets
+		probe map[string]interface{}
+	)
+
+	if err := yaml.Unmarshal(data, &probe); err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+
+	if _, ok := probe["scenarios"]; ok {
+		err = yaml.UnmarshalStrict(data, &file)
+		if err == nil {
+			err = yaml.Unmarshal(data, &set)
+		}
+	} else {
+		file.Scenarios = make([]benchmark.Parameter, 1)
+		set.Scenarios = make([]setParameter, 1)
+		err = yaml.UnmarshalStrict(data, &file.Scenarios[0])
+		if err == nil {
+			err = yaml.Unmarshal(data, &set.Scenarios[0])
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+
+	name := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+
+	for i := range file.Scenarios {
+		s := &file.Scenarios[i]
+
+		if s.Name == "" {
+			s.Name = name
+			if len(file.Scenarios) > 1 {
+				s.Name = fmt.Sprintf("%s-%d", name, i+1)
+			}
+		}
+
+		m.defaults(s, set.Scenarios[i])
+
+		if err := m.validate(*s); err != nil {
+			return nil, fmt.Errorf("%s: scenario %s: %v", path, s.Name, err)
+		}
+	}
+
+	return file.Scenarios, nil
+}
+
+func (m Module) defaults(s *benchmark.Parameter, set setParameter) {
+	d := m.config.Defaults
+
+	if len(s.Clients) == 0 {
+		s.Clients = d.Clients
+	}
+	if len(s.Operations) == 0 {
+		s.Operations = d.Operations
+	}
+	if s.Iterations == 0 && s.Duration == 0 {
+		s.Iterations, s.Duration = d.Iterations, d.Duration
+	}
+	if s.Warmup == 0 {
+		s.Warmup = d.Warmup
+	}
+	if s.Workers == 0 {
+		s.Workers = d.Workers
+	}
+	if s.QPS == 0 {
+		s.QPS = d.QPS
+	}
+	if s.RampUp == 0 {
+		s.RampUp = d.RampUp
+	}
+	if s.OrderID == 0 {
+		s.OrderID = d.OrderID
+	}
+	if s.Seed == 0 {
+		s.Seed = d.Seed
+	}
+	if s.RefreshWait == 0 {
+		s.RefreshWait = d.RefreshWait
+	}
+	if s.Faults == nil {
+		s.Faults = d.Faults
+	}
+	if set.Verbose == nil {
+		s.Verbose = d.Verbose
+	}
+	if set.Profile == nil {
+		s.Profile = d.Profile
+	}
+	if set.OpenLoop == nil {
+		s.OpenLoop = d.OpenLoop
+	}
+
+	for i := range s.Operations {
+		o := &s.Operations[i]
+
+		// the file's own operations, a weight of 0 is rejected rather than taken for a missing one
+		if i < len(set.Operations) && set.Operations[i].Weight == nil {
+			o.Weight = 1
+		}
+		if o.Query == "" {
+			o.Query = m.config.Operation.Query
+		}
+		if o.BatchSize == 0 {
+			o.BatchSize = m.config.Operation.BatchSize
+		}
+
+		o.Sort = normalize(o.Sort).(map[string]interface{})
+	}
+}
+
+func (m Module) validate(s benchmark.Parameter) error {
+	for _, client := range s.Clients {
+		if !contains(m.config.Clients, client) {
+			return fmt.Errorf("unknown client %q", client)
+		}
+	}
+
+	if len(s.Operations) == 0 {
+		return fmt.Errorf("no operations")
+	}
+
+	if s.OpenLoop && s.QPS <= 0 {
+		return fmt.Errorf("open loop needs a qps")
+	}
+
+	if f := s.Faults; f != nil {
+		if f.Latency < 0 || f.Jitter < 0 {
+			return fmt.Errorf("faults: negative latency")
+		}
+
+		for _, rate := range []float64{f.LatencyRate, f.TooManyRequests, f.Unavailable, f.Truncate, f.Reset} {
+			if rate < 0 || rate > 1 {
+				return fmt.Errorf("faults: rate %v is not between 0 and 1", rate)
+			}
+		}
+
+		if total := f.TooManyRequests + f.Unavailable + f.Truncate + f.Reset; total > 1 {
+			return fmt.Errorf("faults: error rates add up to %v, more than 1", total)
+		}
+	}
+
+	for _, o := range s.Operations {
+		if !contains(benchmark.Operations, o.Name) {
+			return fmt.Errorf("unknown operation %q", o.Name)
+		}
+
+		if o.Weight <= 0 {
+			return fmt.Errorf("operation %s: weight %d, leave the operation out instead", o.Name, o.Weight)
+		}
+
+		// the documents of a bulk follow the order id, they have to fit in the namespace of the run
+		if o.Name == elasticEntity.OperationBulk && (o.BatchSize < 1 || o.BatchSize >= tracker.Size) {
+			return fmt.Errorf("operation %s: batch size %d is not between 1 and %d", o.Name, o.BatchSize, tracker.Size-1)
+		}
+
+		if o.TimeRange != nil {
+			for _, date := range []string{o.TimeRange.From, o.TimeRange.To} {
+				if date == "" && o.TimeRange.Last > 0 {
+					continue
+				}
+
+				if _, err := time.Parse("2006-01-02", date); err != nil {
+					return fmt.Errorf("operation %s: time range: %v", o.Name, err)
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+func normalize(v interface{}) interface{} {
+	switch value := v.(type) {
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(value))
+		for k, item := range value {
+			m[fmt.Sprint(k)] = normalize(item)
+		}
+		return m
+	case map[string]interface{}:
+		for k, item := range value {
+			value[k] = normalize(item)
+		}
+		return value
+	case []interface{}:
+		for i, item := range value {
+			value[i] = normalize(item)
+		}
+		return value
+	}
+
+	return v
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,133 @@
+package scenario
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/elastic-fray/entity/benchmark"
+)
+
+var defaults = benchmark.Parameter{
+	Clients:     []string{"api", "officialclient"},
+	Operations:  []benchmark.Operation{{Name: "search", Weight: 1, Query: "*"}},
+	Iterations:  1,
+	Workers:     4,
+	QPS:         100,
+	Verbose:     true,
+	Profile:     true,
+	RefreshWait: time.Second,
+}
+
+func TestLoad(t *testing.T) {
+	for _, tc := range []struct {
+		name  string
+		file  string
+		check func(parameters []benchmark.Parameter) string
+	}{
+		{"defaults", `
+workers: 8
+`, func(p []benchmark.Parameter) string {
+			s := p[0]
+			if s.Name != "defaults" || s.Workers != 8 || s.QPS != 100 || s.Iterations != 1 || !reflect.DeepEqual(s.Clients, defaults.Clients) {
+				return "flags did not fill in what the scenario left out"
+			}
+			if !s.Verbose || !s.Profile || s.OpenLoop {
+				return "booleans left out did not follow the flags"
+			}
+			if len(s.Operations) != 1 || s.Operations[0].Weight != 1 {
+				return "operations did not come from the flags"
+			}
+			return ""
+		}},
+		{"booleans turned off", `
+verbose: false
+profile: false
+`, func(p []benchmark.Parameter) string {
+			if p[0].Verbose || p[0].Profile {
+				return "the scenario could not turn off a flag"
+			}
+			return ""
+		}},
+		{"weights", `
+operations:
+  - name: search
+  - name: count
+    weight: 3
+`, func(p []benchmark.Parameter) string {
+			o := p[0].Operations
+			if o[0].Weight != 1 || o[1].Weight != 3 {
+				return fmt.Sprintf("weights are %d %d, want 1 3", o[0].Weight, o[1].Weight)
+			}
+			if o[0].Query != "*" {
+				return "operation query did not come from the flags"
+			}
+			return ""
+		}},
+		{"several", `
+scenarios:
+  - workers: 1
+  - name: named
+    open_loop: true
+`, func(p []benchmark.Parameter) string {
+			if len(p) != 2 || p[0].Name != "several-1" || p[1].Name != "named" {
+				return "scenarios are not named after the file"
+			}
+			if p[0].OpenLoop || !p[1].OpenLoop {
+				return "open_loop leaked between scenarios"
+			}
+			return ""
+		}},
+	} {
+		parameters, err := load(t, tc.name, tc.file)
+		if err != nil {
+			t.Errorf("%s: %v", tc.name, err)
+			continue
+		}
+
+		if problem := tc.check(parameters); problem != "" {
+			t.Errorf("%s: %s", tc.name, problem)
+		}
+	}
+}
+
+func TestLoadRejects(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		file string
+		want string
+	}{
+		{"unknown field", "workerz: 8", "field workerz not found"},
+		{"unknown client", "clients: [nethttp]", `unknown client "nethttp"`},
+		{"unknown operation", "operations: [{name: scan}]", `unknown operation "scan"`},
+		{"zero weight", "operations: [{name: search, weight: 0}]", "weight 0"},
+		{"negative weight", "operations: [{name: search, weight: -1}]", "weight -1"},
+		{"open loop without qps", "{open_loop: true, qps: -1}", "open loop needs a qps"},
+		{"fault rate", "faults: {unavailable: 2}", "not between 0 and 1"},
+		{"fault total", "faults: {unavailable: 0.6, reset: 0.6}", "add up to"},
+		{"empty bulk", "operations: [{name: bulk, batch_size: -1}]", "batch size -1"},
+		{"bulk past the namespace", "operations: [{name: bulk, batch_size: 100000}]", "batch size 100000"},
+		{"time range", "operations: [{name: search, time_range: {from: yesterday}}]", "time range"},
+	} {
+		_, err := load(t, tc.name, tc.file)
+		if err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("%s: error = %v, want %q", tc.name, err, tc.want)
+		}
+	}
+}
+
+func load(t *testing.T, name, file string) ([]benchmark.Parameter, error) {
+	path := filepath.Join(t.TempDir(), strings.Replace(name, " ", "-", -1)+".yaml")
+	if err := ioutil.WriteFile(path, []byte(file), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	return New(Config{
+		Defaults:  defaults,
+		Operation: benchmark.Operation{Query: "*"},
+	}).Load(path)
+}
@@ -0,0 +1,38 @@
+package scenario
+
+import (
+	"github.com/elastic-fray/entity/benchmark"
+)
+
+type (
+	Method interface {
+		Load(path string) ([]benchmark.Parameter, error)
+	}
+)
+
+type (
+	Config struct {
+		Defaults  benchmark.Parameter // used for every field a scenario leaves empty
+		Operation benchmark.Operation // same, for every operation of a scenario
+		Clients   []string            // known clients, benchmark.Clients when empty
+	}
+
+	Module struct {
+		config Config
+	}
+
+	setScenarios struct {
+		Scenarios []setParameter `yaml:"scenarios"`
+	}
+
+	setParameter struct {
+		Verbose    *bool          `yaml:"verbose"`
+		Profile    *bool          `yaml:"profile"`
+		OpenLoop   *bool          `yaml:"open_loop"`
+		Operations []setOperation `yaml:"operations"`
+	}
+
+	setOperation struct {
+		Weight *int `yaml:"weight"`
+	}
+)
@@ -0,0 +1,112 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/robfig/cron/v3"
+
+	"github.com/tokopedia/tdk/go/log"
+)
+
+func New(c Config) (Method, error) {
+	if c.Location == nil {
+		c.Location = time.Local
+	}
+
+	m := Module{
+		config: c,
+	}
+
+	switch {
+	case c.Cron != "" && c.Every > 0:
+		return m, errors.New("scheduler: set either every or cron, not both")
+	case c.Cron != "":
+		schedule, err := cron.ParseStandard(c.Cron)
+		if err != nil {
+			return m, fmt.Errorf("scheduler: %v", err)
+		}
+
+		m.schedule = schedule
+	case c.Every <= 0:
+		return m, errors.New("scheduler: either every or cron must be set")
+	}
+
+	return m, nil
+}
+
+func (m Module) Run(ctx context.Context, job func(ctx context.Context) error) error {
+	next := time.Now().In(m.config.Location)
+	if m.schedule != nil {
+		next = m.Next(next)
+	}
+
+	for {
+		if !m.config.Until.IsZero() && next.After(m.config.Until) {
+			log.Infof("scheduler: next run at %s is past %s, stopping", next.Format(time.RFC3339), m.config.Until.Format(time.RFC3339))
+			return nil
+		}
+
+		log.Infof("scheduler: next run at %s", next.Format(time.RFC3339))
+
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil
+		case <-timer.C:
+		}
+
+		if err := job(ctx); err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+
+			log.Error("scheduler: ", err)
+		}
+
+		next = m.Next(next)
+		for now := time.Now(); next.Before(now); next = m.Next(next) {
+			log.Infof("scheduler: skipping run at %s, the previous one overran", next.Format(time.RFC3339))
+		}
+	}
+}
+
+func (m Module) Next(after time.Time) time.Time {
+	after = after.In(m.config.Location)
+
+	if m.schedule != nil {
+		return m.schedule.Next(after)
+	}
+
+	return after.Add(m.config.Every)
+}
+
+func ParseUntil(value string, now time.Time, location *time.Location) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+
+	if t, err := time.ParseInLocation("2006-01-02 15:04", value, location); err == nil {
+		return t, nil
+	}
+
+	clock, err := time.ParseInLocation("15:04", value, location)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("scheduler: cannot parse until %q, use RFC 3339, 2006-01-02 15:04 or 15:04", value)
+	}
+
+	now = now.In(location)
+	t := time.Date(now.Year(), now.Month(), now.Day(), clock.Hour(), clock.Minute(), 0, 0, location)
+	if !t.After(now) {
+		t = t.AddDate(0, 0, 1)
+	}
+
+	return t, nil
+}
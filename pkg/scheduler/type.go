@@ -0,0 +1,29 @@
+package scheduler
+
+import (
+	"context"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+type (
+	Method interface {
+		Run(ctx context.Context, job func(ctx context.Context) error) error
+		Next(after time.Time) time.Time
+	}
+)
+
+type (
+	Config struct {
+		Every    time.Duration // run right away and then every period
+		Cron     string        // or on a standard 5 field cron expression
+		Location *time.Location
+		Until    time.Time // no run starts after it, zero means until ctx is done
+	}
+
+	Module struct {
+		config   Config
+		schedule cron.Schedule
+	}
+)
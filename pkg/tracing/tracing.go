@@ -0,0 +1,83 @@
+package tracing
+
+import (
+	"context"
+	"io"
+	"os"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/sdk/resource"
+	"go.opentelemetry.io/otel/trace"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+
+	"github.com/elastic-fray/pkg/monitor"
+)
+
+const instrumentation = "github.com/elastic-fray"
+
+func New(c Config) (Method, error) {
+	var (
+		m           = Module{}
+		w io.Writer = os.Stdout
+	)
+
+	if c.File != "" && c.File != "-" {
+		file, err := os.Create(c.File)
+		if err != nil {
+			return nil, err
+		}
+
+		m.file, w = file, file
+	}
+
+	exporter, err := stdouttrace.New(stdouttrace.WithWriter(w))
+	if err != nil {
+		return nil, err
+	}
+
+	m.provider = sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(resource.NewSchemaless(attribute.String("service.name", c.Service))),
+	)
+	otel.SetTracerProvider(m.provider)
+
+	return m, nil
+}
+
+func (m Module) Shutdown(ctx context.Context) error {
+	err := m.provider.Shutdown(ctx)
+
+	if m.file != nil {
+		if closeErr := m.file.Close(); err == nil {
+			err = closeErr
+		}
+	}
+
+	return err
+}
+
+func Start(ctx context.Context, name string, attributes ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer(instrumentation).Start(ctx, name, trace.WithAttributes(attributes...))
+}
+
+func StartRequest(ctx context.Context, name string, attributes ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer(instrumentation).Start(ctx, name, trace.WithAttributes(attributes...), trace.WithSpanKind(trace.SpanKindClient))
+}
+
+func End(ctx context.Context, span trace.Span, err error) {
+	if status := monitor.Status(ctx); status > 0 {
+		span.SetAttributes(AttributeStatus.Int(status))
+	}
+
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		span.SetAttributes(AttributeError.Bool(true), AttributeErrorClass.String(monitor.ErrorClass(err)))
+	}
+
+	span.End()
+}
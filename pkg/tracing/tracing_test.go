@@ -0,0 +1,75 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/elastic-fray/pkg/monitor"
+)
+
+func TestSpans(t *testing.T) {
+	dir, err := ioutil.TempDir("", "tracing")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	file := filepath.Join(dir, "trace.json")
+
+	m, err := New(Config{
+		File:    file,
+		Service: "elastic-fray",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := monitor.WithStatus(context.Background())
+
+	ctx, parent := Start(ctx, "api.GetPromoOrderUsage", AttributeClient.String("api"), AttributeIndex.String("staging_promo_order_usage"))
+	_, span := StartRequest(ctx, "sauron.Search")
+	monitor.SetStatus(ctx, 503)
+	End(ctx, span, errors.New("unavailable"))
+	End(ctx, parent, nil)
+
+	if err := m.Shutdown(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	body, err := ioutil.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(string(body)), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("got %d spans, want 2:\n%s", len(lines), body)
+	}
+
+	for _, want := range []string{
+		`"Name":"sauron.Search"`,
+		`"SpanKind":3`,
+		`"Key":"http.status_code","Value":{"Type":"INT64","Value":503}`,
+		`"Key":"error.class","Value":{"Type":"STRING","Value":"other"}`,
+		`"Status":{"Code":"Error","Description":"unavailable"}`,
+	} {
+		if !strings.Contains(lines[0], want) {
+			t.Errorf("request span lacks %s, got:\n%s", want, lines[0])
+		}
+	}
+
+	for _, want := range []string{
+		`"Name":"api.GetPromoOrderUsage"`,
+		`"Key":"elastic.index","Value":{"Type":"STRING","Value":"staging_promo_order_usage"}`,
+		`"Key":"service.name","Value":{"Type":"STRING","Value":"elastic-fray"}`,
+	} {
+		if !strings.Contains(lines[1], want) {
+			t.Errorf("span lacks %s, got:\n%s", want, lines[1])
+		}
+	}
+}
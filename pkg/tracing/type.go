@@ -0,0 +1,39 @@
+package tracing
+
+import (
+	"context"
+	"os"
+
+	"go.opentelemetry.io/otel/attribute"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+const (
+	AttributeClient     = attribute.Key("elastic.client")
+	AttributeOperation  = attribute.Key("elastic.operation")
+	AttributeIndex      = attribute.Key("elastic.index")
+	AttributeDocumentID = attribute.Key("elastic.document_id")
+	AttributeHits       = attribute.Key("elastic.hits")
+	AttributeStatus     = attribute.Key("http.status_code")
+	AttributeError      = attribute.Key("error")
+	AttributeErrorClass = attribute.Key("error.class")
+)
+
+type (
+	Method interface {
+		Shutdown(ctx context.Context) error // exports what is left and closes the file
+	}
+)
+
+type (
+	Config struct {
+		File    string // spans are written there as JSON lines, stdout when empty or "-"
+		Service string // service.name of the spans
+	}
+
+	Module struct {
+		provider *sdktrace.TracerProvider
+		file     *os.File // nil for stdout
+	}
+)
@@ -0,0 +1,78 @@
+package tracker
+
+import (
+	"math/rand"
+	"sort"
+	"time"
+)
+
+const first = 900000000000000
+
+func New(c Config) Method {
+	if c.Base == 0 {
+		random := rand.New(rand.NewSource(time.Now().UnixNano()))
+		c.Base = first + random.Int63n(1000000000)*Size
+	}
+
+	return Module{
+		base: c.Base,
+		state: &state{
+			documents: make(map[string]map[int64]bool),
+		},
+	}
+}
+
+func (m Module) Base() int64 {
+	return m.base
+}
+
+func (m Module) Track(client string, orderIDs ...int64) {
+	m.state.mutex.Lock()
+	defer m.state.mutex.Unlock()
+
+	documents, ok := m.state.documents[client]
+	if !ok {
+		documents = make(map[int64]bool)
+		m.state.documents[client] = documents
+	}
+
+	for _, orderID := range orderIDs {
+		documents[orderID] = true
+	}
+}
+
+func (m Module) Untrack(client string, orderIDs ...int64) {
+	m.state.mutex.Lock()
+	defer m.state.mutex.Unlock()
+
+	documents := m.state.documents[client]
+	for _, orderID := range orderIDs {
+		delete(documents, orderID)
+	}
+
+	if len(documents) == 0 {
+		delete(m.state.documents, client)
+	}
+}
+
+func (m Module) Tracked() map[string][]int64 {
+	m.state.mutex.Lock()
+	defer m.state.mutex.Unlock()
+
+	tracked := make(map[string][]int64, len(m.state.documents))
+
+	for client, documents := range m.state.documents {
+		orderIDs := make([]int64, 0, len(documents))
+		for orderID := range documents {
+			orderIDs = append(orderIDs, orderID)
+		}
+
+		sort.Slice(orderIDs, func(i, j int) bool {
+			return orderIDs[i] < orderIDs[j]
+		})
+
+		tracked[client] = orderIDs
+	}
+
+	return tracked
+}
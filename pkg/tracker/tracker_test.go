@@ -0,0 +1,42 @@
+package tracker
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestBase(t *testing.T) {
+	a, b := New(Config{}).Base(), New(Config{}).Base()
+
+	for _, base := range []int64{a, b} {
+		if base < first || (base-first)%Size != 0 {
+			t.Errorf("base %d is not the start of a namespace", base)
+		}
+	}
+
+	if a == b {
+		t.Errorf("two runs share the namespace %d", a)
+	}
+
+	if base := New(Config{Base: 69696969}).Base(); base != 69696969 {
+		t.Errorf("base = %d, want the configured 69696969", base)
+	}
+}
+
+func TestTracked(t *testing.T) {
+	m := New(Config{Base: 100})
+
+	m.Track("api", 103, 101, 102)
+	m.Track("officialclient", 101)
+	m.Track("api", 101)
+	m.Untrack("api", 102)
+	m.Untrack("officialclient", 101)
+
+	want := map[string][]int64{
+		"api": {101, 103},
+	}
+
+	if got := m.Tracked(); !reflect.DeepEqual(got, want) {
+		t.Errorf("tracked = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,32 @@
+package tracker
+
+import (
+	"sync"
+)
+
+const Size = 100000
+
+type (
+	Method interface {
+		Base() int64
+		Track(client string, orderIDs ...int64)
+		Untrack(client string, orderIDs ...int64)
+		Tracked() map[string][]int64
+	}
+)
+
+type (
+	Config struct {
+		Base int64 // first order id of the namespace, a random one when 0
+	}
+
+	Module struct {
+		base  int64
+		state *state
+	}
+
+	state struct {
+		mutex     sync.Mutex
+		documents map[string]map[int64]bool // order ids by the client that wrote them
+	}
+)
@@ -1,9 +1,14 @@
 package utils
 
+import "time"
+
 type (
 	Config struct {
 		Server        ServerConfig
 		Datadog       DatadogConfig
+		Monitor       MonitorConfig
+		Prometheus    PrometheusConfig
+		Tracing       TracingConfig
 		ElasticSearch ElasticSearchConfig
 	}
 
@@ -15,7 +20,22 @@ type (
 		Connection string
 	}
 
+	MonitorConfig struct {
+		Backend       string
+		QueueSize     int
+		FlushInterval time.Duration
+	}
+
+	PrometheusConfig struct {
+		Address string
+	}
+
+	TracingConfig struct {
+		File string
+	}
+
 	ElasticSearchConfig struct {
-		URL string
+		URL   string
+		Index string
 	}
 )
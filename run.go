@@ -0,0 +1,288 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/elastic-fray/entity/benchmark"
+	"github.com/elastic-fray/pkg/dashboard"
+	"github.com/elastic-fray/pkg/history"
+	"github.com/elastic-fray/pkg/report"
+	"github.com/elastic-fray/pkg/tracker"
+
+	benchmarkUsecase "github.com/elastic-fray/usecase/benchmark"
+
+	"github.com/tokopedia/tdk/go/log"
+)
+
+type runFlags struct {
+	*workloadFlags
+	reports  string
+	baseline string
+	results  string
+	live     bool
+}
+
+func newRunFlags(flags *flag.FlagSet) *runFlags {
+	r := &runFlags{
+		workloadFlags: newWorkloadFlags(flags, benchmark.Operations),
+	}
+
+	flags.IntVar(&r.parameter.Iterations, "iterations", 1, "number of passes over the operations, ignored when -duration is set")
+	flags.DurationVar(&r.parameter.Duration, "duration", 0, "keep running passes for this long instead of a fixed number of iterations")
+	flags.DurationVar(&r.parameter.Warmup, "warmup", 0, "run the workload for this long before measuring, its samples are discarded")
+	flags.IntVar(&r.parameter.Workers, "workers", 1, "number of concurrent workers")
+	flags.Float64Var(&r.parameter.QPS, "qps", 0, "target requests per second across all workers, 0 for unlimited")
+	flags.BoolVar(&r.parameter.OpenLoop, "open-loop", false, "send at -qps whatever the response times and measure latency from when each request was due")
+	flags.DurationVar(&r.parameter.RampUp, "ramp-up", 0, "ramp the rate linearly up to -qps over this period, or stagger worker starts when -qps is 0")
+	flags.BoolVar(&r.parameter.Verbose, "verbose", false, "print the result of every request")
+	flags.BoolVar(&r.parameter.Profile, "profile", false, "capture CPU, heap and allocs profiles of each client into the run's results directory")
+	flags.DurationVar(&r.parameter.RefreshWait, "refresh-wait", time.Second, "time to wait before delete so the index is refreshed")
+	flags.StringVar(&r.reports, "report", "", "comma separated comparison report files, the format follows the extension: .md, .json, .csv")
+	flags.StringVar(&r.baseline, "baseline", "", "client the report compares against, defaults to the first of -client")
+	flags.StringVar(&r.results, "results", "results", "directory the run is saved to for later comparison, empty to skip")
+	flags.BoolVar(&r.live, "live", true, "show the progress of each client live, when the output is a terminal and not -verbose")
+
+	return r
+}
+
+func run(args []string) {
+	flags := flag.NewFlagSet("run", flag.ExitOnError)
+	configFlags(flags)
+	options := newRunFlags(flags)
+	flags.Parse(args)
+
+	parameters := options.load()
+	options.check(parameters)
+
+	setup(clients(parameters), hasFaults(parameters))
+	options.setupDashboard(parameters)
+
+	err := runBenchmark(Context, parameters, options, false)
+
+	shutdown()
+
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+func runBenchmark(ctx context.Context, parameters []benchmark.Parameter, options *runFlags, tagReports bool) error {
+	record := benchmark.Run{
+		StartedAt:  time.Now().In(Location),
+		Revision:   revision(),
+		Versions:   versions(),
+		Target:     target(),
+		Parameters: parameters,
+	}
+	record.ID = history.ID(record.StartedAt, record.Revision)
+	record.Namespace = Tracker.Base()
+
+	runner := newBenchmark()
+	defer teardown(runner)
+
+	if Metrics != nil {
+		flushMetrics()
+		Metrics.Reset() // of the previous scheduled run and its teardown
+	}
+
+	reporter := report.New(report.Config{
+		Baseline: options.baseline,
+	})
+
+	for _, parameter := range parameters {
+		if len(parameters) > 1 {
+			fmt.Printf("\n== %s\n", parameter.Name)
+		}
+
+		result, err := runner.Run(ctx, parameter)
+		if err != nil {
+			return err
+		}
+
+		if result.OpenLoop {
+			fmt.Printf("open loop at %v req/s, latency counts from when each request was due\n", parameter.QPS)
+		}
+		if result.Faults != nil {
+			fmt.Printf("faults: %s\n", report.DescribeFaults(*result.Faults))
+		}
+		printStats(result.Stats)
+		printProfiles(result.Profiles)
+
+		if err = saveProfiles(filepath.Join(options.results, record.ID), &result); err != nil {
+			return err
+		}
+		record.Results = append(record.Results, result)
+
+		comparison, err := reporter.Build(result)
+		if err != nil {
+			return err
+		}
+
+		for _, path := range splitList(options.reports) {
+			var suffix string
+			if len(parameters) > 1 {
+				suffix += "-" + parameter.Name
+			}
+			if tagReports {
+				suffix += "-" + record.ID
+			}
+
+			path = strings.TrimSuffix(path, filepath.Ext(path)) + suffix + filepath.Ext(path)
+
+			if err = reporter.WriteFile(path, comparison); err != nil {
+				return err
+			}
+		}
+	}
+
+	if Metrics != nil {
+		flushMetrics()
+		fmt.Println()
+		Metrics.WriteSummary(os.Stdout)
+	}
+
+	if options.results != "" {
+		if _, err := history.New(history.Config{Dir: options.results}).Save(record); err != nil {
+			return err
+		}
+
+		fmt.Printf("\nsaved run %s to %s\n", record.ID, options.results)
+	}
+
+	return nil
+}
+
+func teardown(runner benchmarkUsecase.Method) {
+	if fixtureDirs.replay != "" {
+		return // nothing was written
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	deleted, err := runner.Teardown(ctx)
+	if err != nil {
+		log.Errorf("teardown: %v, documents may be left with order ids %d to %d", err, Tracker.Base(), Tracker.Base()+tracker.Size-1)
+	}
+
+	if deleted > 0 {
+		fmt.Printf("\ndeleted %d test documents\n", deleted)
+	}
+}
+
+func (r *runFlags) check(parameters []benchmark.Parameter) {
+	for _, parameter := range parameters {
+		if parameter.Profile && r.results == "" {
+			log.Fatal("profiles are saved with the results, -profile needs -results")
+		}
+
+		if r.baseline != "" && !contains(parameter.Clients, r.baseline) {
+			log.Fatalf("-baseline %s is not a client of scenario %s", r.baseline, parameter.Name)
+		}
+	}
+}
+
+func (r *runFlags) setupDashboard(parameters []benchmark.Parameter) {
+	if !r.live || !isTerminal(os.Stdout) {
+		return
+	}
+
+	for _, parameter := range parameters {
+		if parameter.Verbose {
+			return
+		}
+	}
+
+	Dashboard = dashboard.New(dashboard.Config{
+		Writer: os.Stdout,
+	})
+}
+
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+func saveProfiles(dir string, result *benchmark.Result) error {
+	if len(result.Profiles) == 0 {
+		return nil
+	}
+
+	dir = filepath.Join(dir, "profiles")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	for i := range result.Profiles {
+		p := &result.Profiles[i]
+
+		for kind, data := range map[string][]byte{
+			"cpu":         p.CPU,
+			"heap":        p.Heap,
+			"allocs":      p.AllocsProfile,
+			"allocs-base": p.AllocsBase,
+		} {
+			name := fmt.Sprintf("%s-%s.%s.pprof", result.Scenario, p.Client, kind)
+
+			if err := ioutil.WriteFile(filepath.Join(dir, name), data, 0644); err != nil {
+				return err
+			}
+			p.Files = append(p.Files, filepath.Join("profiles", name))
+		}
+
+		sort.Strings(p.Files)
+	}
+
+	return nil
+}
+
+func printProfiles(profiles []benchmark.Profile) {
+	if len(profiles) == 0 {
+		return
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(w, "\nCLIENT\tREQUESTS\tALLOCS/OP\tBYTES/OP\t")
+
+	for _, p := range profiles {
+		fmt.Fprintf(w, "%s\t%d\t%.0f\t%.0f\t\n", p.Client, p.Requests, p.AllocsPerOp, p.BytesPerOp)
+	}
+
+	w.Flush()
+}
+
+func printStats(stats []benchmark.Stats) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(w, "CLIENT\tOPERATION\tCOUNT\tERRORS\tREQ/S\tMEAN\tP50\tP90\tP99\tP99.9\tMAX\t")
+
+	for _, s := range stats {
+		fmt.Fprintf(w, "%s\t%s\t%d\t%d\t%.1f\t%s\t%s\t%s\t%s\t%s\t%s\t\n",
+			s.Client, s.Operation, s.Count, s.Errors, s.Throughput(),
+			round(s.Mean), round(s.P50), round(s.P90), round(s.P99), round(s.P999), round(s.Max))
+	}
+
+	w.Flush()
+}
+
+func round(d time.Duration) time.Duration {
+	return d.Round(time.Microsecond)
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+
+	return false
+}
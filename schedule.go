@@ -0,0 +1,58 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"time"
+
+	"github.com/elastic-fray/pkg/scheduler"
+
+	"github.com/tokopedia/tdk/go/log"
+)
+
+func schedule(args []string) {
+	var (
+		every time.Duration
+		spec  string
+		until string
+	)
+
+	flags := flag.NewFlagSet("schedule", flag.ExitOnError)
+	configFlags(flags)
+	options := newRunFlags(flags)
+	flags.DurationVar(&every, "every", 0, "run the benchmark right away and then every period, e.g. 60m")
+	flags.StringVar(&spec, "cron", "", "or run it on a cron expression in Asia/Jakarta, e.g. \"0 * * * *\" for every hour")
+	flags.StringVar(&until, "until", "", "do not start runs after this time: 15:04, 2006-01-02 15:04 or RFC 3339, in Asia/Jakarta")
+	flags.Parse(args)
+
+	stop, err := scheduler.ParseUntil(until, time.Now(), Location)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	runs, err := scheduler.New(scheduler.Config{
+		Every:    every,
+		Cron:     spec,
+		Location: Location,
+		Until:    stop,
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	parameters := options.load()
+	options.check(parameters)
+
+	setup(clients(parameters), hasFaults(parameters))
+	options.setupDashboard(parameters)
+
+	err = runs.Run(Context, func(ctx context.Context) error {
+		return runBenchmark(ctx, parameters, options, true)
+	})
+
+	shutdown()
+
+	if err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,474 @@
+package benchmark
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/elastic-fray/entity/benchmark"
+	elasticEntity "github.com/elastic-fray/entity/elastic"
+	"github.com/elastic-fray/entity/promo/marketplace"
+	"github.com/elastic-fray/pkg/dashboard"
+	"github.com/elastic-fray/pkg/generator"
+	"github.com/elastic-fray/pkg/loadgen"
+	"github.com/elastic-fray/pkg/monitor"
+	"github.com/elastic-fray/pkg/profile"
+	"github.com/elastic-fray/pkg/recorder"
+	"github.com/elastic-fray/pkg/tracker"
+	"github.com/elastic-fray/usecase/driver"
+
+	"github.com/tokopedia/tdk/go/log"
+
+	"github.com/tokopedia/sauron/src/elastic"
+)
+
+var (
+	once sync.Once
+	m    Module
+)
+
+var (
+	operationLabel = map[string]string{
+		elasticEntity.OperationSearch: "Search",
+		elasticEntity.OperationCount:  "Count",
+		elasticEntity.OperationInsert: "Insert",
+		elasticEntity.OperationUpdate: "Update",
+		elasticEntity.OperationDelete: "Delete",
+		elasticEntity.OperationBulk:   "Bulk",
+	}
+)
+
+func New(c Config) Method {
+	once.Do(func() {
+		if c.Config.ElasticSearch.Index == "" {
+			c.Config.ElasticSearch.Index = elastic.ConstElasticSearchIndexPromoOrderUsage
+		}
+
+		if c.Location == nil {
+			c.Location = time.Local
+		}
+
+		if c.Tracker == nil {
+			c.Tracker = tracker.New(tracker.Config{})
+		}
+
+		m = Module{
+			config:    c.Config,
+			location:  c.Location,
+			monitor:   c.Monitor,
+			faults:    c.Faults,
+			dashboard: c.Dashboard,
+			tracker:   c.Tracker,
+			usecase: Usecase{
+				drivers: c.Drivers,
+			},
+		}
+	})
+
+	return m
+}
+
+func (m Module) Run(ctx context.Context, parameter benchmark.Parameter) (benchmark.Result, error) {
+	var (
+		result = benchmark.Result{
+			Scenario: parameter.Name,
+			Clients:  parameter.Clients,
+			OpenLoop: parameter.OpenLoop,
+			Faults:   parameter.Faults,
+		}
+		latencies = recorder.New()
+		elapsed   = make(map[string]time.Duration)
+	)
+
+	if parameter.Faults != nil {
+		if m.faults == nil {
+			return result, errors.New("scenario has faults but no fault injection is set up")
+		}
+
+		result.Injected = make(map[string]map[string]int64)
+		defer m.faults.Set(benchmark.Faults{}, 0)
+	}
+
+	if m.dashboard != nil {
+		defer m.dashboard.Stop()
+	}
+
+	if parameter.OrderID == 0 {
+		parameter.OrderID = m.tracker.Base()
+	}
+
+	for _, client := range parameter.Clients {
+		if parameter.Faults != nil {
+			m.faults.Set(*parameter.Faults, parameter.Seed)
+		}
+
+		window, profiled, err := m.runClient(ctx, client, parameter, latencies)
+		elapsed[client] = window
+
+		if profiled != nil {
+			profiled.Client = client
+			result.Profiles = append(result.Profiles, *profiled)
+		}
+
+		if parameter.Faults != nil {
+			result.Injected[client] = m.faults.Injected()
+		}
+
+		if err != nil {
+			log.Error(err)
+			return result, err
+		}
+	}
+
+	result.Stats = latencies.Snapshot()
+	for i := range result.Stats {
+		result.Stats[i].Elapsed = elapsed[result.Stats[i].Client]
+	}
+
+	for i := range result.Profiles {
+		p := &result.Profiles[i]
+
+		for _, s := range result.Stats {
+			if s.Client == p.Client {
+				p.Requests += s.Count
+			}
+		}
+
+		if p.Requests > 0 {
+			p.AllocsPerOp = float64(p.Allocs) / float64(p.Requests)
+			p.BytesPerOp = float64(p.AllocBytes) / float64(p.Requests)
+		}
+	}
+
+	return result, nil
+}
+
+func (m Module) runClient(ctx context.Context, client string, parameter benchmark.Parameter, latencies recorder.Method) (time.Duration, *benchmark.Profile, error) {
+	if len(parameter.Operations) == 0 {
+		return 0, nil, errors.New("no operations to run")
+	}
+
+	d, err := driver.Get(client)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	var (
+		operations = make([]operation, 0, len(parameter.Operations))
+		documents  = generator.New(generator.Config{
+			Seed:     parameter.Seed,
+			Location: m.location,
+		})
+	)
+
+	for _, o := range parameter.Operations {
+		op, err := m.operation(client, o, parameter, documents)
+		if err != nil {
+			return 0, nil, err
+		}
+
+		operations = append(operations, op)
+	}
+
+	schedule := weighted(operations)
+	if len(schedule) == 0 {
+		return 0, nil, errors.New("all operations have zero weight")
+	}
+
+	defer m.monitor.SetHistogram(time.Now(), d.Metric, []string{monitor.TagClient + ":" + client})
+
+	rampUp := parameter.RampUp
+
+	if parameter.Warmup > 0 {
+		warmup := recorder.New()
+		m.watch(dashboard.Phase{
+			Scenario: parameter.Name,
+			Client:   client,
+			Name:     "warmup",
+			Recorder: warmup,
+			Duration: parameter.Warmup,
+		})
+
+		err := loadgen.New(loadgen.Config{
+			Workers:  parameter.Workers,
+			QPS:      parameter.QPS,
+			RampUp:   rampUp,
+			Duration: parameter.Warmup,
+			OpenLoop: parameter.OpenLoop,
+		}).Run(ctx, m.job(d, schedule, parameter, warmup))
+		if err != nil {
+			return 0, nil, err
+		}
+
+		rampUp = 0 // already at full rate
+	}
+
+	var profiler profile.Method
+	if parameter.Profile {
+		profiler = profile.New(profile.Config{})
+		if err := profiler.Start(); err != nil {
+			return 0, nil, err
+		}
+	}
+
+	m.watch(dashboard.Phase{
+		Scenario: parameter.Name,
+		Client:   client,
+		Name:     "measurement",
+		Recorder: latencies,
+		Duration: parameter.Duration,
+		Requests: m.iterations(parameter, len(schedule)),
+	})
+
+	start := time.Now()
+
+	err = loadgen.New(loadgen.Config{
+		Workers:    parameter.Workers,
+		QPS:        parameter.QPS,
+		RampUp:     rampUp,
+		Duration:   parameter.Duration,
+		Iterations: m.iterations(parameter, len(schedule)),
+		OpenLoop:   parameter.OpenLoop,
+	}).Run(ctx, m.job(d, schedule, parameter, latencies))
+
+	window := time.Since(start)
+
+	var profiled *benchmark.Profile
+	if profiler != nil {
+		p, stopErr := profiler.Stop()
+		if stopErr != nil {
+			return window, nil, stopErr
+		}
+		profiled = &p
+	}
+
+	if err != nil {
+		return 0, profiled, err
+	}
+
+	return window, profiled, ctx.Err()
+}
+
+func (m Module) watch(phase dashboard.Phase) {
+	if m.dashboard != nil {
+		m.dashboard.Watch(phase)
+	}
+}
+
+func (m Module) job(d driver.Driver, schedule []operation, parameter benchmark.Parameter, latencies recorder.Method) loadgen.Job {
+	return func(ctx context.Context, sequence int64, due time.Time) {
+		op := schedule[sequence%int64(len(schedule))]
+
+		if op.name == elasticEntity.OperationDelete && parameter.RefreshWait > 0 {
+			// let the index refresh before deleting
+			timer := time.NewTimer(parameter.RefreshWait)
+			select {
+			case <-timer.C:
+			case <-ctx.Done():
+				timer.Stop()
+				return
+			}
+			due = due.Add(parameter.RefreshWait)
+		}
+
+		// an open loop counts the time a request waited past its schedule,
+		// the latency a user arriving at that rate would see
+		start := time.Now()
+		if parameter.OpenLoop {
+			start = due
+		}
+
+		result, err := op.do(ctx)
+		latencies.Record(d.Name, op.name, time.Since(start), err)
+
+		if err != nil {
+			log.Error(err)
+			return
+		}
+
+		if parameter.Verbose && result != "" {
+			fmt.Printf("%s %s - %s\n", d.Label, op.label, result)
+		}
+	}
+}
+
+func (m Module) iterations(parameter benchmark.Parameter, schedule int) int64 {
+	if parameter.Duration > 0 {
+		return 0
+	}
+
+	return int64(parameter.Iterations) * int64(schedule)
+}
+
+func weighted(operations []operation) []operation {
+	var (
+		total    int
+		current  = make([]int, len(operations))
+		schedule []operation
+	)
+
+	for _, op := range operations {
+		total += op.weight
+	}
+
+	for n := 0; n < total; n++ {
+		best := 0
+
+		for i, op := range operations {
+			current[i] += op.weight
+			if current[i] > current[best] {
+				best = i
+			}
+		}
+
+		current[best] -= total
+		schedule = append(schedule, operations[best])
+	}
+
+	return schedule
+}
+
+func (m Module) operation(client string, o benchmark.Operation, parameter benchmark.Parameter, documents generator.Method) (operation, error) {
+	d, err := m.driver(client)
+	if err != nil {
+		return operation{}, err
+	}
+
+	var do func(ctx context.Context) (string, error)
+
+	switch o.Name {
+	case elasticEntity.OperationSearch:
+		do = func(ctx context.Context) (string, error) {
+			resp, err := d.Search(ctx, m.searchParameter(o, client+".benchmark"))
+			return fmt.Sprint("Total Result: ", len(resp)), err
+		}
+	case elasticEntity.OperationCount:
+		do = func(ctx context.Context) (string, error) {
+			resp, err := d.Count(ctx, m.searchParameter(o, client+".benchmark"))
+			return fmt.Sprint("Total Result: ", resp), err
+		}
+	case elasticEntity.OperationInsert:
+		promo := documents.Promo(parameter.OrderID)
+		m.tracker.Track(client, promo.OrderID)
+
+		do = func(ctx context.Context) (string, error) {
+			return "", d.Insert(ctx, promo)
+		}
+	case elasticEntity.OperationUpdate:
+		promo := documents.Promo(parameter.OrderID)
+		m.tracker.Track(client, promo.OrderID) // both clients upsert
+
+		do = func(ctx context.Context) (string, error) {
+			return "", d.Update(ctx, promo)
+		}
+	case elasticEntity.OperationDelete:
+		do = func(ctx context.Context) (string, error) {
+			resp, err := d.Delete(ctx, parameter.OrderID)
+			return fmt.Sprint("Status: ", resp), err
+		}
+	case elasticEntity.OperationBulk:
+		promos := documents.Promos(parameter.OrderID+1, o.BatchSize)
+		for _, promo := range promos {
+			m.tracker.Track(client, promo.OrderID)
+		}
+
+		body, err := m.bulkBody(promos)
+		if err != nil {
+			return operation{}, err
+		}
+
+		do = func(ctx context.Context) (string, error) {
+			resp, err := d.Bulk(ctx, body)
+			return fmt.Sprint("Status: ", resp), err
+		}
+	default:
+		return operation{}, fmt.Errorf("unknown operation: %s", o.Name)
+	}
+
+	return operation{
+		name:   o.Name,
+		label:  operationLabel[o.Name],
+		weight: o.Weight,
+		do:     do,
+	}, nil
+}
+
+func (m Module) driver(client string) (driver.Method, error) {
+	d, ok := m.usecase.drivers[client]
+	if !ok {
+		return nil, fmt.Errorf("client %s is not set up", client)
+	}
+
+	return d, nil
+}
+
+func (m Module) searchParameter(o benchmark.Operation, source string) elasticEntity.ElasticSearchParameter {
+	parameter := elasticEntity.ElasticSearchParameter{
+		QueryString: o.Query,
+		Size:        o.Size,
+		Sort:        o.Sort,
+		PreferNode:  o.PreferNode,
+		Source:      source,
+	}
+
+	if o.TimeRange != nil {
+		parameter.IsUsingTime = true
+		parameter.GTE, parameter.LTE = m.timeRange(*o.TimeRange)
+	}
+
+	return parameter
+}
+
+func (m Module) timeRange(t benchmark.TimeRange) (time.Time, time.Time) {
+	if t.Last > 0 {
+		lte := time.Now().In(m.location)
+		return lte.Add(-t.Last), lte
+	}
+
+	gte, _ := time.ParseInLocation("2006-01-02", t.From, m.location)
+	lte, _ := time.ParseInLocation("2006-01-02", t.To, m.location)
+
+	return gte, lte
+}
+
+func (m Module) bulkBody(promos []marketplace.Promo) (string, error) {
+	var buffer bytes.Buffer
+
+	for _, promo := range promos {
+		index, err := json.Marshal(elasticEntity.IndexBulkInsert{
+			Index: elasticEntity.BulkInsert{
+				Index: m.index(),
+				Type:  "order",
+				ID:    strconv.FormatInt(promo.OrderID, 10),
+			},
+		})
+		if err != nil {
+			return "", err
+		}
+
+		data, err := json.Marshal(elasticEntity.PromoOrderUsageBulkInsert{
+			Doc: promo,
+		})
+		if err != nil {
+			return "", err
+		}
+
+		buffer.WriteString(fmt.Sprintf("%s\n%s\n", index, data))
+	}
+
+	return buffer.String(), nil
+}
+
+func (m Module) index() string {
+	environment := m.config.Server.Environment
+	if environment == "development" {
+		environment = "staging"
+	}
+
+	return environment + "-" + m.config.ElasticSearch.Index
+}
@@ -0,0 +1,68 @@
+package benchmark
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strconv"
+
+	elasticEntity "github.com/elastic-fray/entity/elastic"
+)
+
+const teardownBatch = 1000
+
+func (m Module) Teardown(ctx context.Context) (int, error) {
+	var deleted int
+
+	for client, orderIDs := range m.tracker.Tracked() {
+		d, err := m.driver(client)
+		if err != nil {
+			return deleted, err
+		}
+
+		for len(orderIDs) > 0 {
+			batch := orderIDs
+			if len(batch) > teardownBatch {
+				batch = batch[:teardownBatch]
+			}
+			orderIDs = orderIDs[len(batch):]
+
+			body, err := m.deleteBody(batch)
+			if err != nil {
+				return deleted, err
+			}
+
+			// a document already gone fails its item, not the request
+			if _, err := d.Bulk(ctx, body); err != nil {
+				return deleted, err
+			}
+
+			m.tracker.Untrack(client, batch...)
+			deleted += len(batch)
+		}
+	}
+
+	return deleted, nil
+}
+
+func (m Module) deleteBody(orderIDs []int64) (string, error) {
+	var buffer bytes.Buffer
+
+	for _, orderID := range orderIDs {
+		action, err := json.Marshal(elasticEntity.DeleteBulk{
+			Delete: elasticEntity.BulkInsert{
+				Index: m.index(),
+				Type:  "order",
+				ID:    strconv.FormatInt(orderID, 10),
+			},
+		})
+		if err != nil {
+			return "", err
+		}
+
+		buffer.Write(action)
+		buffer.WriteByte('\n')
+	}
+
+	return buffer.String(), nil
+}
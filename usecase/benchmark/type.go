@@ -0,0 +1,57 @@
+package benchmark
+
+import (
+	"context"
+	"time"
+
+	"github.com/elastic-fray/entity/benchmark"
+	"github.com/elastic-fray/pkg/dashboard"
+	"github.com/elastic-fray/pkg/fault"
+	"github.com/elastic-fray/pkg/monitor"
+	"github.com/elastic-fray/pkg/tracker"
+	"github.com/elastic-fray/pkg/utils"
+	"github.com/elastic-fray/usecase/driver"
+)
+
+const verifySize = 100 // documents compared by search when the operation sets no size
+
+type (
+	Method interface {
+		Run(ctx context.Context, parameter benchmark.Parameter) (benchmark.Result, error)
+		Verify(ctx context.Context, parameter benchmark.Parameter) (benchmark.Verification, error)
+		Teardown(ctx context.Context) (int, error)
+	}
+)
+
+type (
+	Config struct {
+		Config    utils.Config
+		Location  *time.Location
+		Monitor   monitor.Method
+		Drivers   map[string]driver.Method // by client name, one for each client the scenarios use
+		Faults    fault.Method             // optional, needed by scenarios with faults
+		Dashboard dashboard.Method         // optional, shows the progress live
+		Tracker   tracker.Method           // namespace of the order ids, a random one when nil
+	}
+
+	Usecase struct {
+		drivers map[string]driver.Method
+	}
+
+	Module struct {
+		config    utils.Config
+		location  *time.Location
+		monitor   monitor.Method
+		faults    fault.Method
+		dashboard dashboard.Method
+		tracker   tracker.Method
+		usecase   Usecase
+	}
+
+	operation struct {
+		name   string
+		label  string
+		weight int
+		do     func(ctx context.Context) (string, error)
+	}
+)
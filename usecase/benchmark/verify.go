@@ -0,0 +1,235 @@
+package benchmark
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/elastic-fray/entity/benchmark"
+	elasticEntity "github.com/elastic-fray/entity/elastic"
+	"github.com/elastic-fray/entity/promo/marketplace"
+)
+
+func (m Module) Verify(ctx context.Context, parameter benchmark.Parameter) (benchmark.Verification, error) {
+	verification := benchmark.Verification{
+		Scenario: parameter.Name,
+	}
+
+	if len(parameter.Clients) < 2 {
+		return verification, errors.New("verify needs at least two clients to compare")
+	}
+
+	for _, o := range parameter.Operations {
+		if err := ctx.Err(); err != nil {
+			return verification, err
+		}
+
+		switch o.Name {
+		case elasticEntity.OperationSearch:
+			verification.Checks = append(verification.Checks, m.verifySearch(ctx, parameter.Clients, o))
+		case elasticEntity.OperationCount:
+			verification.Checks = append(verification.Checks, m.verifyCount(ctx, parameter.Clients, o))
+		}
+	}
+
+	return verification, nil
+}
+
+func (m Module) verifySearch(ctx context.Context, clients []string, o benchmark.Operation) benchmark.Check {
+	var (
+		parameter = m.searchParameter(o, "verify")
+		results   = make([][]marketplace.Promo, len(clients))
+		errs      = make([]error, len(clients))
+		check     = benchmark.Check{
+			Operation: o.Name,
+			Query:     o.Query,
+		}
+	)
+
+	// the same documents from every client, whatever the scenario sorts on and however the shards tie
+	parameter.Sort = map[string]interface{}{"order_id": "asc"}
+	if parameter.Size == 0 {
+		parameter.Size = verifySize
+	}
+
+	for i, client := range clients {
+		d, err := m.driver(client)
+		if err == nil {
+			results[i], err = d.Search(ctx, parameter)
+		}
+
+		errs[i] = err
+		check.Totals = append(check.Totals, benchmark.Total{Client: client, Total: len(results[i])})
+	}
+
+	check.Error = checkError(clients, errs)
+
+	if check.Error == "" {
+		for i := 1; i < len(clients); i++ {
+			check.Mismatches = append(check.Mismatches, diffPromos(clients[0], clients[i], results[0], results[i])...)
+		}
+		check.Match = len(check.Mismatches) == 0
+	}
+
+	return check
+}
+
+func (m Module) verifyCount(ctx context.Context, clients []string, o benchmark.Operation) benchmark.Check {
+	var (
+		parameter = m.searchParameter(o, "verify")
+		errs      = make([]error, len(clients))
+		check     = benchmark.Check{
+			Operation: o.Name,
+			Query:     o.Query,
+		}
+	)
+
+	for i, client := range clients {
+		var count int
+
+		d, err := m.driver(client)
+		if err == nil {
+			count, err = d.Count(ctx, parameter)
+		}
+
+		errs[i] = err
+		check.Totals = append(check.Totals, benchmark.Total{Client: client, Total: count})
+	}
+
+	check.Error = checkError(clients, errs)
+	check.Match = check.Error == ""
+
+	for _, total := range check.Totals {
+		check.Match = check.Match && total.Total == check.Totals[0].Total
+	}
+
+	return check
+}
+
+func checkError(clients []string, errs []error) string {
+	var messages []string
+
+	for i, err := range errs {
+		if err != nil {
+			messages = append(messages, clients[i]+": "+err.Error())
+		}
+	}
+
+	return strings.Join(messages, "; ")
+}
+
+func diffPromos(first, client string, expected, actual []marketplace.Promo) []benchmark.Mismatch {
+	var (
+		mismatches []benchmark.Mismatch
+		expects    = groupPromos(expected)
+		actuals    = groupPromos(actual)
+		orderIDs   []int64
+	)
+
+	for orderID := range expects {
+		orderIDs = append(orderIDs, orderID)
+	}
+	for orderID := range actuals {
+		if _, ok := expects[orderID]; !ok {
+			orderIDs = append(orderIDs, orderID)
+		}
+	}
+
+	sort.Slice(orderIDs, func(i, j int) bool {
+		return orderIDs[i] < orderIDs[j]
+	})
+
+	for _, orderID := range orderIDs {
+		e, a := expects[orderID], actuals[orderID]
+
+		switch {
+		case len(a) == 0:
+			mismatches = append(mismatches, benchmark.Mismatch{Client: client, OrderID: orderID, Reason: "missing in " + client})
+		case len(e) == 0:
+			mismatches = append(mismatches, benchmark.Mismatch{Client: client, OrderID: orderID, Reason: "missing in " + first})
+		case len(e) != len(a):
+			mismatches = append(mismatches, benchmark.Mismatch{
+				Client:  client,
+				OrderID: orderID,
+				Reason:  fmt.Sprintf("returned %d times by %s, %d times by %s", len(e), first, len(a), client),
+			})
+		default:
+			for i := range e {
+				if fields := diffFields(e[i], a[i]); len(fields) > 0 {
+					mismatches = append(mismatches, benchmark.Mismatch{
+						Client:  client,
+						OrderID: orderID,
+						Reason:  "documents differ from " + first,
+						Fields:  fields,
+					})
+				}
+			}
+		}
+	}
+
+	return mismatches
+}
+
+func groupPromos(promos []marketplace.Promo) map[int64][]marketplace.Promo {
+	group := make(map[int64][]marketplace.Promo, len(promos))
+
+	for _, promo := range promos {
+		group[promo.OrderID] = append(group[promo.OrderID], promo)
+	}
+
+	return group
+}
+
+func diffFields(a, b marketplace.Promo) []string {
+	if reflect.DeepEqual(a, b) {
+		return nil
+	}
+
+	var fields []string
+	diffValues("", toJSONValue(a), toJSONValue(b), &fields)
+	sort.Strings(fields)
+
+	return fields
+}
+
+func toJSONValue(v interface{}) interface{} {
+	var value interface{}
+
+	data, _ := json.Marshal(v)
+	json.Unmarshal(data, &value)
+
+	return value
+}
+
+func diffValues(path string, a, b interface{}, fields *[]string) {
+	am, aok := a.(map[string]interface{})
+	bm, bok := b.(map[string]interface{})
+
+	if !aok || !bok {
+		if !reflect.DeepEqual(a, b) {
+			*fields = append(*fields, path)
+		}
+		return
+	}
+
+	keys := make(map[string]bool)
+	for k := range am {
+		keys[k] = true
+	}
+	for k := range bm {
+		keys[k] = true
+	}
+
+	for k := range keys {
+		p := k
+		if path != "" {
+			p = path + "." + k
+		}
+
+		diffValues(p, am[k], bm[k], fields)
+	}
+}
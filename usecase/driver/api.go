@@ -0,0 +1,54 @@
+package driver
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	elasticEntity "github.com/elastic-fray/entity/elastic"
+	"github.com/elastic-fray/entity/promo/marketplace"
+	"github.com/elastic-fray/usecase/elastic/api"
+)
+
+func openAPI(c Config) (Method, error) {
+	c, err := throughProxy(c)
+	if err != nil {
+		return nil, err
+	}
+
+	return apiDriver{
+		usecase: api.New(api.Config{
+			Config:   c.Config,
+			Datadog:  c.Datadog,
+			Location: c.Location,
+			Monitor:  c.Monitor,
+		}),
+		url: c.Config.ElasticSearch.URL,
+	}, nil
+}
+
+func (d apiDriver) Search(ctx context.Context, parameter elasticEntity.ElasticSearchParameter) ([]marketplace.Promo, error) {
+	return d.usecase.GetPromoOrderUsage(ctx, parameter)
+}
+
+func (d apiDriver) Count(ctx context.Context, parameter elasticEntity.ElasticSearchParameter) (int, error) {
+	return d.usecase.CountPromoOrderUsage(ctx, parameter)
+}
+
+func (d apiDriver) Insert(ctx context.Context, promo marketplace.Promo) error {
+	return d.usecase.InsertPromoOrderUsage(ctx, promo)
+}
+
+func (d apiDriver) Update(ctx context.Context, promo marketplace.Promo) error {
+	return d.usecase.UpdatePromoOrderUsage(ctx, promo)
+}
+
+func (d apiDriver) Delete(ctx context.Context, orderID int64) (string, error) {
+	deleted, err := d.usecase.DeletePromoOrderUsage(ctx, "order_id:"+strconv.FormatInt(orderID, 10))
+	return fmt.Sprint("deleted ", deleted), err
+}
+
+func (d apiDriver) Bulk(ctx context.Context, body string) (string, error) {
+	resp, err := d.usecase.BulkPromoOrderUsage(ctx, d.url, body)
+	return fmt.Sprint(resp), err
+}
@@ -0,0 +1,118 @@
+package driver
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"sort"
+	"sync"
+
+	elasticEntity "github.com/elastic-fray/entity/elastic"
+	"github.com/elastic-fray/pkg/proxy"
+)
+
+var (
+	mutex   sync.RWMutex
+	drivers = make(map[string]Driver)
+	proxies []*http.Server
+)
+
+func init() {
+	Register(Driver{
+		Name:   elasticEntity.ClientAPI,
+		Label:  "API",
+		Metric: "handler.elastic.api.get.promo.order.usage",
+		Open:   openAPI,
+	})
+	Register(Driver{
+		Name:   elasticEntity.ClientOfficialClient,
+		Label:  "Official Client",
+		Metric: "handler.elastic.official.client.get.promo.order.usage",
+		Open:   openOfficialClient,
+	})
+	Register(Driver{
+		Name:   elasticEntity.ClientNetHTTP,
+		Label:  "net/http",
+		Metric: "handler.elastic.nethttp.get.promo.order.usage",
+		Open:   openNetHTTP,
+	})
+}
+
+func Register(d Driver) {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	drivers[d.Name] = d
+}
+
+func Get(name string) (Driver, error) {
+	mutex.RLock()
+	defer mutex.RUnlock()
+
+	d, ok := drivers[name]
+	if !ok {
+		return Driver{}, fmt.Errorf("unknown client: %s", name)
+	}
+
+	return d, nil
+}
+
+func Names() []string {
+	mutex.RLock()
+	defer mutex.RUnlock()
+
+	names := make([]string, 0, len(drivers))
+	for name := range drivers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+func Close() error {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	var err error
+	for _, server := range proxies {
+		if e := server.Close(); e != nil && err == nil {
+			err = e
+		}
+	}
+	proxies = nil
+
+	return err
+}
+
+func throughProxy(c Config) (Config, error) {
+	if c.Transport == nil {
+		return c, nil
+	}
+
+	target := c.Config.ElasticSearch.URL
+	if target == "" {
+		target = "http://replay" // replaying needs no cluster
+	}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return c, err
+	}
+
+	server := &http.Server{
+		Handler: proxy.New(proxy.Config{
+			Target:    target,
+			Transport: c.Transport,
+		}),
+	}
+	go server.Serve(listener)
+
+	mutex.Lock()
+	proxies = append(proxies, server)
+	mutex.Unlock()
+
+	c.Config.ElasticSearch.URL = "http://" + listener.Addr().String()
+
+	return c, nil
+}
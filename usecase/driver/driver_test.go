@@ -0,0 +1,210 @@
+package driver
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ooyala/go-dogstatsd"
+
+	elasticEntity "github.com/elastic-fray/entity/elastic"
+	"github.com/elastic-fray/entity/promo/marketplace"
+	"github.com/elastic-fray/pkg/elastic/fake"
+	"github.com/elastic-fray/pkg/monitor/memory"
+	"github.com/elastic-fray/pkg/utils"
+)
+
+const testIndex = "staging-promo-order-usage"
+
+var (
+	testDrivers  = make(map[string]Method)
+	testMonitors = make(map[string]memory.Method)
+	testServer   fake.Method
+)
+
+func TestMain(m *testing.M) {
+	testServer = fake.New(fake.Config{})
+
+	datadog, err := dogstatsd.New("127.0.0.1:8125")
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	for _, name := range Names() {
+		d, _ := Get(name)
+		testMonitors[name] = memory.New()
+
+		testDrivers[name], err = d.Open(Config{
+			Config: utils.Config{
+				Server: utils.ServerConfig{
+					Environment: "staging",
+				},
+				ElasticSearch: utils.ElasticSearchConfig{
+					URL: testServer.URL(),
+				},
+			},
+			Datadog:  datadog,
+			Location: time.UTC,
+			Monitor:  testMonitors[name],
+		})
+		if err != nil {
+			fmt.Fprintln(os.Stderr, name, err)
+			os.Exit(1)
+		}
+	}
+
+	code := m.Run()
+	testServer.Close()
+	os.Exit(code)
+}
+
+func TestDrivers(t *testing.T) {
+	for name, d := range testDrivers {
+		t.Run(name, func(t *testing.T) {
+			testMonitors[name].Reset()
+			testDriver(t, d)
+			testMetrics(t, name)
+		})
+	}
+}
+
+func testDriver(t *testing.T, d Method) {
+	ctx := context.Background()
+	testServer.Reset()
+
+	promo := marketplace.Promo{
+		OrderID:    69696969,
+		Source:     "marketplace",
+		Platform:   "android",
+		CreateTime: time.Date(2020, 5, 14, 10, 0, 0, 0, time.UTC),
+	}
+
+	if err := d.Insert(ctx, promo); err != nil {
+		t.Fatal(err)
+	}
+
+	promo.Platform = "ios"
+	if err := d.Update(ctx, promo); err != nil {
+		t.Fatal(err)
+	}
+
+	promos, err := d.Search(ctx, elasticEntity.ElasticSearchParameter{QueryString: "platform:ios"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(promos) != 1 || promos[0].OrderID != promo.OrderID {
+		t.Fatalf("search = %+v, want order %d", promos, promo.OrderID)
+	}
+
+	count, err := d.Count(ctx, elasticEntity.ElasticSearchParameter{
+		QueryString: "source:marketplace",
+		IsUsingTime: true,
+		GTE:         time.Date(2020, 5, 14, 0, 0, 0, 0, time.UTC),
+		LTE:         time.Date(2020, 5, 14, 0, 0, 0, 0, time.UTC),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 1 {
+		t.Errorf("count on the day = %d, want 1", count)
+	}
+
+	if _, err := d.Delete(ctx, promo.OrderID); err != nil {
+		t.Fatal(err)
+	}
+	if n := testServer.Count(testIndex); n != 0 {
+		t.Errorf("documents after delete = %d, want 0", n)
+	}
+
+	var body strings.Builder
+	for i := 1; i <= 3; i++ {
+		fmt.Fprintf(&body, `{"index":{"_index":"%s","_type":"order","_id":"%d"}}`+"\n", testIndex, i)
+		fmt.Fprintf(&body, `{"doc":{"order_id":%d,"source":"marketplace"}}`+"\n", i)
+	}
+
+	if _, err := d.Bulk(ctx, body.String()); err != nil {
+		t.Fatal(err)
+	}
+	if n := testServer.Count(testIndex); n != 3 {
+		t.Errorf("documents after bulk = %d, want 3", n)
+	}
+}
+
+func testMetrics(t *testing.T, name string) {
+	m := testMonitors[name]
+
+	for _, operation := range []string{"get", "count", "insert", "update", "delete", "bulk"} {
+		metric := "usecase.elastic." + name + "." + operation + ".promo.order.usage"
+
+		if n := len(m.Durations(metric)); n != 1 {
+			t.Errorf("%s recorded %d times, want once, recorded: %v", metric, n, m.Names())
+		}
+
+		if n := m.Count(metric, "client:"+name, "index:promo-order-usage", "outcome:success"); n != 1 {
+			t.Errorf("%s lacks the standard tags: %v", metric, m.Samples(metric))
+		}
+	}
+
+	// sauron does not tell the status of its responses
+	if name == elasticEntity.ClientAPI {
+		return
+	}
+
+	metric := "usecase.elastic." + name + ".get.promo.order.usage"
+	if n := m.Count(metric, "operation:search", "status_code:200"); n != 1 {
+		t.Errorf("%s lacks the status: %v", metric, m.Samples(metric))
+	}
+}
+
+func TestGet(t *testing.T) {
+	if _, err := Get("elastigo"); err == nil {
+		t.Error("unknown client found")
+	}
+
+	Register(Driver{Name: "elastigo"})
+	defer func() {
+		mutex.Lock()
+		delete(drivers, "elastigo")
+		mutex.Unlock()
+	}()
+
+	if _, err := Get("elastigo"); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestClose(t *testing.T) {
+	c, err := throughProxy(Config{
+		Config: utils.Config{
+			ElasticSearch: utils.ElasticSearchConfig{
+				URL: testServer.URL(),
+			},
+		},
+		Transport: http.DefaultTransport,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	client := &http.Client{Transport: &http.Transport{}}
+
+	resp, err := client.Get(c.Config.ElasticSearch.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	if err := Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if resp, err := client.Get(c.Config.ElasticSearch.URL); err == nil {
+		resp.Body.Close()
+		t.Error("the proxy still answers after Close")
+	}
+}
@@ -0,0 +1,148 @@
+package driver
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	elasticEntity "github.com/elastic-fray/entity/elastic"
+	"github.com/elastic-fray/entity/promo/marketplace"
+	"github.com/elastic-fray/pkg/elastic/nethttp"
+	"github.com/elastic-fray/pkg/monitor"
+	"github.com/elastic-fray/pkg/tracing"
+	"github.com/elastic-fray/usecase/elastic/api"
+
+	"github.com/tokopedia/tdk/go/log"
+
+	"github.com/tokopedia/sauron/src/elastic"
+)
+
+func openNetHTTP(c Config) (Method, error) {
+	c, err := throughProxy(c)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.Config.ElasticSearch.Index == "" {
+		c.Config.ElasticSearch.Index = elastic.ConstElasticSearchIndexPromoOrderUsage
+	}
+
+	return netHTTPDriver{
+		config:  c.Config,
+		monitor: c.Monitor,
+		elastic: nethttp.New(nethttp.Config{
+			Config: c.Config,
+		}),
+	}, nil
+}
+
+func (d netHTTPDriver) Search(ctx context.Context, parameter elasticEntity.ElasticSearchParameter) (promos []marketplace.Promo, err error) {
+	ctx, span := d.start(ctx, "Search", elasticEntity.OperationSearch)
+	defer d.record(ctx, span, time.Now(), elasticEntity.OperationSearch, "usecase.elastic.nethttp.get.promo.order.usage", &err)
+
+	var resp elasticEntity.PromoOrderUsage
+
+	if err := d.elastic.ProcessSearch(ctx, &elastic.SearchOption{
+		Label:       "promo.order.usage",
+		Index:       d.config.ElasticSearch.Index,
+		Input:       api.PromoQuery(parameter),
+		Environment: true,
+		Output:      &resp,
+		Size:        parameter.Size,
+		Sort:        parameter.Sort,
+		PreferNode:  parameter.PreferNode,
+	}); err != nil {
+		log.Error(err)
+		return promos, err
+	}
+
+	for _, hit := range resp.Hits.Hits {
+		promos = append(promos, hit.Source)
+	}
+	span.SetAttributes(tracing.AttributeHits.Int(len(promos)))
+
+	return promos, nil
+}
+
+func (d netHTTPDriver) Count(ctx context.Context, parameter elasticEntity.ElasticSearchParameter) (total int, err error) {
+	ctx, span := d.start(ctx, "Count", elasticEntity.OperationCount)
+	defer d.record(ctx, span, time.Now(), elasticEntity.OperationCount, "usecase.elastic.nethttp.count.promo.order.usage", &err)
+
+	total, err = d.elastic.ProcessCount(ctx, &elastic.SearchOption{
+		Label:       "promo.order.usage",
+		Index:       d.config.ElasticSearch.Index,
+		Input:       api.PromoQuery(parameter),
+		Environment: true,
+		PreferNode:  parameter.PreferNode,
+	})
+	span.SetAttributes(tracing.AttributeHits.Int(total))
+
+	return total, err
+}
+
+func (d netHTTPDriver) Insert(ctx context.Context, promo marketplace.Promo) (err error) {
+	ctx, span := d.start(ctx, "Insert", elasticEntity.OperationInsert, tracing.AttributeDocumentID.String(strconv.FormatInt(promo.OrderID, 10)))
+	defer d.record(ctx, span, time.Now(), elasticEntity.OperationInsert, "usecase.elastic.nethttp.insert.promo.order.usage", &err)
+
+	return d.elastic.ProcessInsert(ctx, d.insertOption(promo))
+}
+
+func (d netHTTPDriver) Update(ctx context.Context, promo marketplace.Promo) (err error) {
+	ctx, span := d.start(ctx, "Update", elasticEntity.OperationUpdate, tracing.AttributeDocumentID.String(strconv.FormatInt(promo.OrderID, 10)))
+	defer d.record(ctx, span, time.Now(), elasticEntity.OperationUpdate, "usecase.elastic.nethttp.update.promo.order.usage", &err)
+
+	return d.elastic.ProcessUpdate(ctx, d.insertOption(promo))
+}
+
+func (d netHTTPDriver) Delete(ctx context.Context, orderID int64) (result string, err error) {
+	ctx, span := d.start(ctx, "Delete", elasticEntity.OperationDelete, tracing.AttributeDocumentID.String(strconv.FormatInt(orderID, 10)))
+	defer d.record(ctx, span, time.Now(), elasticEntity.OperationDelete, "usecase.elastic.nethttp.delete.promo.order.usage", &err)
+
+	return d.elastic.ProcessDelete(ctx, strconv.FormatInt(orderID, 10), &elastic.DeleteOption{
+		Environment: true,
+		Index:       d.config.ElasticSearch.Index,
+	})
+}
+
+func (d netHTTPDriver) Bulk(ctx context.Context, body string) (result string, err error) {
+	ctx, span := d.start(ctx, "Bulk", elasticEntity.OperationBulk)
+	defer d.record(ctx, span, time.Now(), elasticEntity.OperationBulk, "usecase.elastic.nethttp.bulk.promo.order.usage", &err)
+
+	failed, err := d.elastic.ProcessBulk(ctx, strings.NewReader(body))
+	return fmt.Sprint("errors: ", failed), err
+}
+
+func (d netHTTPDriver) start(ctx context.Context, method, operation string, attributes ...attribute.KeyValue) (context.Context, trace.Span) {
+	return tracing.Start(monitor.WithStatus(ctx), elasticEntity.ClientNetHTTP+"."+method, append(attributes,
+		tracing.AttributeClient.String(elasticEntity.ClientNetHTTP),
+		tracing.AttributeOperation.String(operation),
+		tracing.AttributeIndex.String(d.config.ElasticSearch.Index),
+	)...)
+}
+
+func (d netHTTPDriver) record(ctx context.Context, span trace.Span, start time.Time, operation, name string, err *error) {
+	d.monitor.SetHistogram(start, name, monitor.Call{
+		Client:    elasticEntity.ClientNetHTTP,
+		Operation: operation,
+		Index:     d.config.ElasticSearch.Index,
+		Status:    monitor.Status(ctx),
+		Err:       *err,
+	}.Tags())
+
+	tracing.End(ctx, span, *err)
+}
+
+func (d netHTTPDriver) insertOption(promo marketplace.Promo) *elastic.InsertOption {
+	return &elastic.InsertOption{
+		Environment: true,
+		Index:       d.config.ElasticSearch.Index,
+		Type:        "order",
+		ID:          strconv.FormatInt(promo.OrderID, 10),
+		Data:        promo,
+	}
+}
@@ -0,0 +1,54 @@
+package driver
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	elasticEntity "github.com/elastic-fray/entity/elastic"
+	"github.com/elastic-fray/entity/promo/marketplace"
+	"github.com/elastic-fray/usecase/elastic/officialclient"
+)
+
+func openOfficialClient(c Config) (Method, error) {
+	c, err := throughProxy(c)
+	if err != nil {
+		return nil, err
+	}
+
+	usecase, err := officialclient.New(officialclient.Config{
+		Config:  c.Config,
+		Monitor: c.Monitor,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return officialClientDriver{
+		usecase: usecase,
+	}, nil
+}
+
+func (d officialClientDriver) Search(ctx context.Context, parameter elasticEntity.ElasticSearchParameter) ([]marketplace.Promo, error) {
+	return d.usecase.GetPromoOrderUsage(ctx, parameter)
+}
+
+func (d officialClientDriver) Count(ctx context.Context, parameter elasticEntity.ElasticSearchParameter) (int, error) {
+	return d.usecase.CountPromoOrderUsage(ctx, parameter)
+}
+
+func (d officialClientDriver) Insert(ctx context.Context, promo marketplace.Promo) error {
+	return d.usecase.InsertPromoOrderUsage(ctx, promo)
+}
+
+func (d officialClientDriver) Update(ctx context.Context, promo marketplace.Promo) error {
+	return d.usecase.UpdatePromoOrderUsage(ctx, promo)
+}
+
+func (d officialClientDriver) Delete(ctx context.Context, orderID int64) (string, error) {
+	return d.usecase.DeletePromoOrderUsage(ctx, strconv.FormatInt(orderID, 10))
+}
+
+func (d officialClientDriver) Bulk(ctx context.Context, body string) (string, error) {
+	return "", d.usecase.BulkPromoOrderUsage(ctx, strings.NewReader(body))
+}
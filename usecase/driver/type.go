@@ -0,0 +1,62 @@
+package driver
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/ooyala/go-dogstatsd"
+
+	elasticEntity "github.com/elastic-fray/entity/elastic"
+	"github.com/elastic-fray/entity/promo/marketplace"
+	"github.com/elastic-fray/pkg/elastic/nethttp"
+	"github.com/elastic-fray/pkg/monitor"
+	"github.com/elastic-fray/pkg/utils"
+	"github.com/elastic-fray/usecase/elastic/api"
+	"github.com/elastic-fray/usecase/elastic/officialclient"
+)
+
+type (
+	Method interface {
+		Search(ctx context.Context, parameter elasticEntity.ElasticSearchParameter) ([]marketplace.Promo, error)
+		Count(ctx context.Context, parameter elasticEntity.ElasticSearchParameter) (int, error)
+		Insert(ctx context.Context, promo marketplace.Promo) error
+		Update(ctx context.Context, promo marketplace.Promo) error
+		Delete(ctx context.Context, orderID int64) (string, error)
+		Bulk(ctx context.Context, body string) (string, error)
+	}
+
+	Open func(c Config) (Method, error)
+)
+
+type (
+	Config struct {
+		Config    utils.Config
+		Datadog   *dogstatsd.Client
+		Location  *time.Location
+		Monitor   monitor.Method
+		Transport http.RoundTripper // optional, sits between the client and the cluster in a local proxy
+	}
+
+	Driver struct {
+		Name   string // used by -client, scenarios and the results
+		Label  string // printed by verbose runs
+		Metric string // histogram of a whole run of the client
+		Open   Open
+	}
+
+	apiDriver struct {
+		usecase api.Method
+		url     string
+	}
+
+	officialClientDriver struct {
+		usecase officialclient.Method
+	}
+
+	netHTTPDriver struct {
+		config  utils.Config
+		monitor monitor.Method
+		elastic nethttp.Method
+	}
+)
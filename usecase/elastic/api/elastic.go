@@ -6,9 +6,14 @@ import (
 	"sync"
 	"time"
 
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
 	elasticEntity "github.com/elastic-fray/entity/elastic"
 	"github.com/elastic-fray/entity/promo/marketplace"
 	"github.com/elastic-fray/pkg/elastic/api"
+	"github.com/elastic-fray/pkg/monitor"
+	"github.com/elastic-fray/pkg/tracing"
 
 	"github.com/tokopedia/tdk/go/log"
 
@@ -22,6 +27,10 @@ var (
 
 func New(c Config) Method {
 	once.Do(func() {
+		if c.Config.ElasticSearch.Index == "" {
+			c.Config.ElasticSearch.Index = elastic.ConstElasticSearchIndexPromoOrderUsage
+		}
+
 		m = Module{
 			config:  c.Config,
 			monitor: c.Monitor,
@@ -38,45 +47,18 @@ func New(c Config) Method {
 	return m
 }
 
-func (m Module) GetPromoOrderUsage(ctx context.Context, parameter elasticEntity.ElasticSearchParameter) ([]marketplace.Promo, error) {
-	defer m.monitor.SetHistogram(time.Now(), "usecase.elastic.api.get.promo.order.usage", nil)
-
-	var (
-		promos []marketplace.Promo
-		resp   elasticEntity.PromoOrderUsage
-	)
-
-	req := elastic.Query{
-		Bool: &elastic.Bool{
-			Must: []elastic.Must{
-				elastic.Must{
-					QueryString: map[string]interface{}{
-						"query": parameter.QueryString,
-					},
-				},
-			},
-		},
-	}
+func (m Module) GetPromoOrderUsage(ctx context.Context, parameter elasticEntity.ElasticSearchParameter) (promos []marketplace.Promo, err error) {
+	ctx, span := m.start(ctx, "GetPromoOrderUsage", elasticEntity.OperationSearch)
+	defer m.record(ctx, span, time.Now(), elasticEntity.OperationSearch, "usecase.elastic.api.get.promo.order.usage", &err)
 
-	if parameter.IsUsingTime {
-		req.Bool.Must = append(req.Bool.Must, elastic.Must{
-			Range: map[string]interface{}{
-				"create_time": map[string]interface{}{
-					"gte":       parameter.GTE.Format("2006-01-02"),
-					"lte":       parameter.LTE.Format("2006-01-02"),
-					"format":    "yyyy-MM-dd",
-					"time_zone": "+07:00",
-				},
-			},
-		})
-	}
+	var resp elasticEntity.PromoOrderUsage
 
 	if err := m.usecase.elastic.Search(ctx, &elastic.SearchOption{
 		URL:         m.config.ElasticSearch.URL,
 		Environment: true,
 		Label:       "promo.order.usage",
-		Index:       elastic.ConstElasticSearchIndexPromoOrderUsage,
-		Input:       req,
+		Index:       m.config.ElasticSearch.Index,
+		Input:       PromoQuery(parameter),
 		Output:      &resp,
 		Size:        parameter.Size,
 		Sort:        parameter.Sort,
@@ -89,39 +71,44 @@ func (m Module) GetPromoOrderUsage(ctx context.Context, parameter elasticEntity.
 	for _, hit := range resp.Hits.Hits {
 		promos = append(promos, hit.Source)
 	}
+	span.SetAttributes(tracing.AttributeHits.Int(len(promos)))
 
 	return promos, nil
 }
 
-func (m Module) CountPromoOrderUsage(ctx context.Context, query string) (int, error) {
-	defer m.monitor.SetHistogram(time.Now(), "usecase.elastic.api.count.promo.order.usage", nil)
+func (m Module) CountPromoOrderUsage(ctx context.Context, parameter elasticEntity.ElasticSearchParameter) (total int, err error) {
+	ctx, span := m.start(ctx, "CountPromoOrderUsage", elasticEntity.OperationCount)
+	defer m.record(ctx, span, time.Now(), elasticEntity.OperationCount, "usecase.elastic.api.count.promo.order.usage", &err)
 
-	total, err := m.usecase.elastic.Count(ctx, &elastic.SearchOption{
+	preferNode := parameter.PreferNode
+	if preferNode == "" {
+		preferNode = elastic.ConstPreferNodeTypeDefault
+	}
+
+	total, err = m.usecase.elastic.Count(ctx, &elastic.SearchOption{
 		URL:         m.config.ElasticSearch.URL,
 		Environment: true,
 		Label:       "promo.order.usage",
-		Index:       elastic.ConstElasticSearchIndexPromoOrderUsage,
-		Input: elastic.Query{
-			QueryString: map[string]interface{}{
-				"query": query,
-			},
-		},
-		PreferNode: elastic.ConstPreferNodeTypeDefault,
+		Index:       m.config.ElasticSearch.Index,
+		Input:       PromoQuery(parameter),
+		PreferNode:  preferNode,
 	})
 	if err != nil {
 		log.Error(err)
 	}
+	span.SetAttributes(tracing.AttributeHits.Int(total))
 
 	return total, err
 }
 
-func (m Module) InsertPromoOrderUsage(ctx context.Context, req marketplace.Promo) error {
-	defer m.monitor.SetHistogram(time.Now(), "usecase.elastic.api.insert.promo.order.usage", nil)
+func (m Module) InsertPromoOrderUsage(ctx context.Context, req marketplace.Promo) (err error) {
+	ctx, span := m.start(ctx, "InsertPromoOrderUsage", elasticEntity.OperationInsert, tracing.AttributeDocumentID.String(strconv.FormatInt(req.OrderID, 10)))
+	defer m.record(ctx, span, time.Now(), elasticEntity.OperationInsert, "usecase.elastic.api.insert.promo.order.usage", &err)
 
-	err := m.usecase.elastic.Insert(ctx, &elastic.InsertOption{
+	err = m.usecase.elastic.Insert(ctx, &elastic.InsertOption{
 		URL:         m.config.ElasticSearch.URL,
 		Environment: true,
-		Index:       elastic.ConstElasticSearchIndexPromoOrderUsage,
+		Index:       m.config.ElasticSearch.Index,
 		Type:        "order",
 		ID:          strconv.FormatInt(req.OrderID, 10),
 		Data:        req,
@@ -133,13 +120,14 @@ func (m Module) InsertPromoOrderUsage(ctx context.Context, req marketplace.Promo
 	return err
 }
 
-func (m Module) UpdatePromoOrderUsage(ctx context.Context, req marketplace.Promo) error {
-	defer m.monitor.SetHistogram(time.Now(), "usecase.elastic.api.update.promo.order.usage", nil)
+func (m Module) UpdatePromoOrderUsage(ctx context.Context, req marketplace.Promo) (err error) {
+	ctx, span := m.start(ctx, "UpdatePromoOrderUsage", elasticEntity.OperationUpdate, tracing.AttributeDocumentID.String(strconv.FormatInt(req.OrderID, 10)))
+	defer m.record(ctx, span, time.Now(), elasticEntity.OperationUpdate, "usecase.elastic.api.update.promo.order.usage", &err)
 
-	err := m.usecase.elastic.Update(ctx, &elastic.InsertOption{
+	err = m.usecase.elastic.Update(ctx, &elastic.InsertOption{
 		URL:         m.config.ElasticSearch.URL,
 		Environment: true,
-		Index:       elastic.ConstElasticSearchIndexPromoOrderUsage,
+		Index:       m.config.ElasticSearch.Index,
 		Type:        "order",
 		ID:          strconv.FormatInt(req.OrderID, 10),
 		Data:        req,
@@ -151,13 +139,14 @@ func (m Module) UpdatePromoOrderUsage(ctx context.Context, req marketplace.Promo
 	return err
 }
 
-func (m Module) DeletePromoOrderUsage(ctx context.Context, query string) (int, error) {
-	defer m.monitor.SetHistogram(time.Now(), "usecase.elastic.api.delete.promo.order.usage", nil)
+func (m Module) DeletePromoOrderUsage(ctx context.Context, query string) (deleted int, err error) {
+	ctx, span := m.start(ctx, "DeletePromoOrderUsage", elasticEntity.OperationDelete)
+	defer m.record(ctx, span, time.Now(), elasticEntity.OperationDelete, "usecase.elastic.api.delete.promo.order.usage", &err)
 
 	resp, err := m.usecase.elastic.Delete(ctx, &elastic.DeleteOption{
 		URL:         m.config.ElasticSearch.URL,
 		Environment: true,
-		Index:       elastic.ConstElasticSearchIndexPromoOrderUsage,
+		Index:       m.config.ElasticSearch.Index,
 		Type:        "order",
 		Query: elastic.Query{
 			Bool: &elastic.Bool{
@@ -184,8 +173,9 @@ func (m Module) DeletePromoOrderUsage(ctx context.Context, query string) (int, e
 	return resp.Deleted, err
 }
 
-func (m Module) BulkPromoOrderUsage(ctx context.Context, url, input string) (bool, error) {
-	defer m.monitor.SetHistogram(time.Now(), "usecase.elastic.api.bulk.promo.order.usage", nil)
+func (m Module) BulkPromoOrderUsage(ctx context.Context, url, input string) (created bool, err error) {
+	ctx, span := m.start(ctx, "BulkPromoOrderUsage", elasticEntity.OperationBulk)
+	defer m.record(ctx, span, time.Now(), elasticEntity.OperationBulk, "usecase.elastic.api.bulk.promo.order.usage", &err)
 
 	resp, err := m.usecase.elastic.Bulk(ctx, url, input)
 	if err != nil {
@@ -194,3 +184,52 @@ func (m Module) BulkPromoOrderUsage(ctx context.Context, url, input string) (boo
 
 	return resp, err
 }
+
+func (m Module) start(ctx context.Context, method, operation string, attributes ...attribute.KeyValue) (context.Context, trace.Span) {
+	return tracing.Start(ctx, elasticEntity.ClientAPI+"."+method, append(attributes,
+		tracing.AttributeClient.String(elasticEntity.ClientAPI),
+		tracing.AttributeOperation.String(operation),
+		tracing.AttributeIndex.String(m.config.ElasticSearch.Index),
+	)...)
+}
+
+func (m Module) record(ctx context.Context, span trace.Span, start time.Time, operation, name string, err *error) {
+	// sauron does not expose its responses, so the status_code is unknown
+	m.monitor.SetHistogram(start, name, monitor.Call{
+		Client:    elasticEntity.ClientAPI,
+		Operation: operation,
+		Index:     m.config.ElasticSearch.Index,
+		Err:       *err,
+	}.Tags())
+
+	tracing.End(ctx, span, *err)
+}
+
+func PromoQuery(parameter elasticEntity.ElasticSearchParameter) elastic.Query {
+	req := elastic.Query{
+		Bool: &elastic.Bool{
+			Must: []elastic.Must{
+				elastic.Must{
+					QueryString: map[string]interface{}{
+						"query": parameter.QueryString,
+					},
+				},
+			},
+		},
+	}
+
+	if parameter.IsUsingTime {
+		req.Bool.Must = append(req.Bool.Must, elastic.Must{
+			Range: map[string]interface{}{
+				"create_time": map[string]interface{}{
+					"gte":       parameter.GTE.Format("2006-01-02"),
+					"lte":       parameter.LTE.Format("2006-01-02"),
+					"format":    "yyyy-MM-dd",
+					"time_zone": "+07:00",
+				},
+			},
+		})
+	}
+
+	return req
+}
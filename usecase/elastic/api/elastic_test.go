@@ -0,0 +1,198 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/ooyala/go-dogstatsd"
+
+	elasticEntity "github.com/elastic-fray/entity/elastic"
+	"github.com/elastic-fray/entity/promo/marketplace"
+	"github.com/elastic-fray/pkg/elastic/fake"
+	"github.com/elastic-fray/pkg/generator"
+	"github.com/elastic-fray/pkg/monitor/memory"
+	"github.com/elastic-fray/pkg/utils"
+)
+
+const benchmarkIndex = "staging-promo-order-usage"
+
+var (
+	benchmarkModule  Method
+	benchmarkServer  fake.Method
+	benchmarkMonitor = memory.New() // reset before each timed loop, its samples would pile up over the runs
+)
+
+func TestMain(m *testing.M) {
+	server, err := newServer(100)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	benchmarkServer = server
+
+	datadog, err := dogstatsd.New("127.0.0.1:8125")
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	benchmarkModule = New(Config{
+		Config: utils.Config{
+			Server: utils.ServerConfig{
+				Environment: "staging",
+			},
+			ElasticSearch: utils.ElasticSearchConfig{
+				URL: server.URL(),
+			},
+		},
+		Datadog:  datadog,
+		Location: time.UTC,
+		Monitor:  benchmarkMonitor,
+	})
+
+	code := m.Run()
+	server.Close()
+	os.Exit(code)
+}
+
+func newServer(documents int) (fake.Method, error) {
+	server := fake.New(fake.Config{})
+
+	for _, promo := range generator.New(generator.Config{Seed: 1}).Promos(1, documents) {
+		if err := server.Put(benchmarkIndex, fmt.Sprint(promo.OrderID), promo); err != nil {
+			server.Close()
+			return nil, err
+		}
+	}
+
+	return server, nil
+}
+
+func seed(b *testing.B, orderID int64) {
+	for _, promo := range generator.New(generator.Config{Seed: 1}).Promos(orderID, b.N) {
+		if err := benchmarkServer.Put(benchmarkIndex, fmt.Sprint(promo.OrderID), promo); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func bulkInput(promos []marketplace.Promo) string {
+	var buffer bytes.Buffer
+
+	for _, promo := range promos {
+		index, _ := json.Marshal(elasticEntity.IndexBulkInsert{
+			Index: elasticEntity.BulkInsert{
+				Index: benchmarkIndex,
+				Type:  "order",
+				ID:    fmt.Sprint(promo.OrderID),
+			},
+		})
+		data, _ := json.Marshal(elasticEntity.PromoOrderUsageBulkInsert{
+			Doc: promo,
+		})
+		buffer.WriteString(fmt.Sprintf("%s\n%s\n", index, data))
+	}
+
+	return buffer.String()
+}
+
+func BenchmarkGetPromoOrderUsage(b *testing.B) {
+	ctx := context.Background()
+	parameter := elasticEntity.ElasticSearchParameter{
+		QueryString: "source:marketplace",
+		Source:      "api.benchmark",
+	}
+
+	b.ReportAllocs()
+	benchmarkMonitor.Reset()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		if _, err := benchmarkModule.GetPromoOrderUsage(ctx, parameter); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkCountPromoOrderUsage(b *testing.B) {
+	ctx := context.Background()
+	parameter := elasticEntity.ElasticSearchParameter{
+		QueryString: "source:marketplace",
+		Source:      "api.benchmark",
+	}
+
+	b.ReportAllocs()
+	benchmarkMonitor.Reset()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		if _, err := benchmarkModule.CountPromoOrderUsage(ctx, parameter); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkInsertPromoOrderUsage(b *testing.B) {
+	ctx := context.Background()
+	promo := generator.New(generator.Config{Seed: 1}).Promo(69696969)
+
+	b.ReportAllocs()
+	benchmarkMonitor.Reset()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		if err := benchmarkModule.InsertPromoOrderUsage(ctx, promo); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkUpdatePromoOrderUsage(b *testing.B) {
+	ctx := context.Background()
+	promo := generator.New(generator.Config{Seed: 1}).Promo(69696969)
+
+	b.ReportAllocs()
+	benchmarkMonitor.Reset()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		if err := benchmarkModule.UpdatePromoOrderUsage(ctx, promo); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkDeletePromoOrderUsage(b *testing.B) {
+	ctx := context.Background()
+	seed(b, 70000000)
+
+	b.ReportAllocs()
+	benchmarkMonitor.Reset()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		if _, err := benchmarkModule.DeletePromoOrderUsage(ctx, fmt.Sprintf("order_id:%d", 70000000+i)); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkBulkPromoOrderUsage(b *testing.B) {
+	ctx := context.Background()
+	input := bulkInput(generator.New(generator.Config{Seed: 1}).Promos(66666666, 50))
+
+	b.ReportAllocs()
+	benchmarkMonitor.Reset()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		if _, err := benchmarkModule.BulkPromoOrderUsage(ctx, benchmarkServer.URL(), input); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -17,7 +17,7 @@ import (
 type (
 	Method interface {
 		GetPromoOrderUsage(ctx context.Context, parameter elasticEntity.ElasticSearchParameter) ([]marketplace.Promo, error)
-		CountPromoOrderUsage(ctx context.Context, query string) (int, error)
+		CountPromoOrderUsage(ctx context.Context, parameter elasticEntity.ElasticSearchParameter) (int, error)
 		InsertPromoOrderUsage(ctx context.Context, req marketplace.Promo) error
 		UpdatePromoOrderUsage(ctx context.Context, req marketplace.Promo) error
 		DeletePromoOrderUsage(ctx context.Context, query string) (int, error)
@@ -7,11 +7,16 @@ import (
 	"sync"
 	"time"
 
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
 	"github.com/elastic/go-elasticsearch/v7/esapi"
 
 	elasticEntity "github.com/elastic-fray/entity/elastic"
 	"github.com/elastic-fray/entity/promo/marketplace"
 	"github.com/elastic-fray/pkg/elastic/officialclient"
+	"github.com/elastic-fray/pkg/monitor"
+	"github.com/elastic-fray/pkg/tracing"
 
 	"github.com/tokopedia/tdk/go/log"
 
@@ -27,6 +32,10 @@ func New(c Config) (Method, error) {
 	var err error
 
 	once.Do(func() {
+		if c.Config.ElasticSearch.Index == "" {
+			c.Config.ElasticSearch.Index = elastic.ConstElasticSearchIndexPromoOrderUsage
+		}
+
 		elastic, err := officialclient.New(officialclient.Config{
 			Config: c.Config,
 		})
@@ -47,19 +56,18 @@ func New(c Config) (Method, error) {
 	return m, err
 }
 
-func (m Module) GetInfo(ctx context.Context, o ...func(*esapi.InfoRequest)) (*esapi.Response, error) {
-	defer m.monitor.SetHistogram(time.Now(), "usecase.elastic.officialclient.get.info", nil)
+func (m Module) GetInfo(ctx context.Context, o ...func(*esapi.InfoRequest)) (res *esapi.Response, err error) {
+	ctx, span := m.start(ctx, "GetInfo", "info")
+	defer m.record(ctx, span, time.Now(), "info", "usecase.elastic.officialclient.get.info", &err)
 
 	return m.usecase.elastic.GetInfo(ctx)
 }
 
-func (m Module) GetPromoOrderUsage(ctx context.Context, parameter elasticEntity.ElasticSearchParameter, o ...func(*esapi.SearchRequest)) ([]marketplace.Promo, error) {
-	defer m.monitor.SetHistogram(time.Now(), "usecase.elastic.officialclient.get.promo.order.usage", nil)
+func (m Module) GetPromoOrderUsage(ctx context.Context, parameter elasticEntity.ElasticSearchParameter, o ...func(*esapi.SearchRequest)) (promos []marketplace.Promo, err error) {
+	ctx, span := m.start(ctx, "GetPromoOrderUsage", elasticEntity.OperationSearch)
+	defer m.record(ctx, span, time.Now(), elasticEntity.OperationSearch, "usecase.elastic.officialclient.get.promo.order.usage", &err)
 
-	var (
-		promos []marketplace.Promo
-		resp   elasticEntity.PromoOrderUsage
-	)
+	var resp elasticEntity.PromoOrderUsage
 
 	req := elastic.Query{
 		Bool: &elastic.Bool{
@@ -89,7 +97,7 @@ func (m Module) GetPromoOrderUsage(ctx context.Context, parameter elasticEntity.
 	if err := m.usecase.elastic.ProcessSearch(ctx, &elastic.SearchOption{
 		URL:         m.config.ElasticSearch.URL,
 		Label:       "promo.order.usage",
-		Index:       elastic.ConstElasticSearchIndexPromoOrderUsage,
+		Index:       m.config.ElasticSearch.Index,
 		Input:       req,
 		Environment: true,
 		Output:      &resp,
@@ -104,12 +112,14 @@ func (m Module) GetPromoOrderUsage(ctx context.Context, parameter elasticEntity.
 	for _, hit := range resp.Hits.Hits {
 		promos = append(promos, hit.Source)
 	}
+	span.SetAttributes(tracing.AttributeHits.Int(len(promos)))
 
 	return promos, nil
 }
 
-func (m Module) CountPromoOrderUsage(ctx context.Context, parameter elasticEntity.ElasticSearchParameter, o ...func(*esapi.SearchRequest)) (int, error) {
-	defer m.monitor.SetHistogram(time.Now(), "usecase.elastic.officialclient.count.promo.order.usage", nil)
+func (m Module) CountPromoOrderUsage(ctx context.Context, parameter elasticEntity.ElasticSearchParameter, o ...func(*esapi.SearchRequest)) (total int, err error) {
+	ctx, span := m.start(ctx, "CountPromoOrderUsage", elasticEntity.OperationCount)
+	defer m.record(ctx, span, time.Now(), elasticEntity.OperationCount, "usecase.elastic.officialclient.count.promo.order.usage", &err)
 
 	req := elastic.Query{
 		Bool: &elastic.Bool{
@@ -136,10 +146,10 @@ func (m Module) CountPromoOrderUsage(ctx context.Context, parameter elasticEntit
 		})
 	}
 
-	total, err := m.usecase.elastic.ProcessCount(ctx, &elastic.SearchOption{
+	total, err = m.usecase.elastic.ProcessCount(ctx, &elastic.SearchOption{
 		URL:         m.config.ElasticSearch.URL,
 		Label:       "promo.order.usage",
-		Index:       elastic.ConstElasticSearchIndexPromoOrderUsage,
+		Index:       m.config.ElasticSearch.Index,
 		Input:       req,
 		Environment: true,
 		PreferNode:  parameter.PreferNode,
@@ -147,17 +157,19 @@ func (m Module) CountPromoOrderUsage(ctx context.Context, parameter elasticEntit
 	if err != nil {
 		log.Error(err)
 	}
+	span.SetAttributes(tracing.AttributeHits.Int(total))
 
 	return total, err
 }
 
-func (m Module) InsertPromoOrderUsage(ctx context.Context, req marketplace.Promo) error {
-	defer m.monitor.SetHistogram(time.Now(), "usecase.elastic.officialclient.insert.promo.order.usage", nil)
+func (m Module) InsertPromoOrderUsage(ctx context.Context, req marketplace.Promo) (err error) {
+	ctx, span := m.start(ctx, "InsertPromoOrderUsage", elasticEntity.OperationInsert, tracing.AttributeDocumentID.String(strconv.FormatInt(req.OrderID, 10)))
+	defer m.record(ctx, span, time.Now(), elasticEntity.OperationInsert, "usecase.elastic.officialclient.insert.promo.order.usage", &err)
 
-	err := m.usecase.elastic.ProcessInsert(ctx, &elastic.InsertOption{
+	err = m.usecase.elastic.ProcessInsert(ctx, &elastic.InsertOption{
 		URL:         m.config.ElasticSearch.URL,
 		Environment: true,
-		Index:       elastic.ConstElasticSearchIndexPromoOrderUsage,
+		Index:       m.config.ElasticSearch.Index,
 		Type:        "order",
 		ID:          strconv.FormatInt(req.OrderID, 10),
 		Data:        req,
@@ -169,13 +181,14 @@ func (m Module) InsertPromoOrderUsage(ctx context.Context, req marketplace.Promo
 	return err
 }
 
-func (m Module) UpdatePromoOrderUsage(ctx context.Context, req marketplace.Promo) error {
-	defer m.monitor.SetHistogram(time.Now(), "usecase.elastic.officialclient.update.promo.order.usage", nil)
+func (m Module) UpdatePromoOrderUsage(ctx context.Context, req marketplace.Promo) (err error) {
+	ctx, span := m.start(ctx, "UpdatePromoOrderUsage", elasticEntity.OperationUpdate, tracing.AttributeDocumentID.String(strconv.FormatInt(req.OrderID, 10)))
+	defer m.record(ctx, span, time.Now(), elasticEntity.OperationUpdate, "usecase.elastic.officialclient.update.promo.order.usage", &err)
 
-	err := m.usecase.elastic.ProcessUpdate(ctx, &elastic.InsertOption{
+	err = m.usecase.elastic.ProcessUpdate(ctx, &elastic.InsertOption{
 		URL:         m.config.ElasticSearch.URL,
 		Environment: true,
-		Index:       elastic.ConstElasticSearchIndexPromoOrderUsage,
+		Index:       m.config.ElasticSearch.Index,
 		Type:        "order",
 		ID:          strconv.FormatInt(req.OrderID, 10),
 		Data:        req,
@@ -187,13 +200,14 @@ func (m Module) UpdatePromoOrderUsage(ctx context.Context, req marketplace.Promo
 	return err
 }
 
-func (m Module) DeletePromoOrderUsage(ctx context.Context, id string) (string, error) {
-	defer m.monitor.SetHistogram(time.Now(), "usecase.elastic.officialclient.delete.promo.order.usage", nil)
+func (m Module) DeletePromoOrderUsage(ctx context.Context, id string) (resp string, err error) {
+	ctx, span := m.start(ctx, "DeletePromoOrderUsage", elasticEntity.OperationDelete, tracing.AttributeDocumentID.String(id))
+	defer m.record(ctx, span, time.Now(), elasticEntity.OperationDelete, "usecase.elastic.officialclient.delete.promo.order.usage", &err)
 
-	resp, err := m.usecase.elastic.ProcessDelete(ctx, id, &elastic.DeleteOption{
+	resp, err = m.usecase.elastic.ProcessDelete(ctx, id, &elastic.DeleteOption{
 		URL:         m.config.ElasticSearch.URL,
 		Environment: true,
-		Index:       elastic.ConstElasticSearchIndexPromoOrderUsage,
+		Index:       m.config.ElasticSearch.Index,
 	})
 	if err != nil {
 		log.Error(err)
@@ -202,13 +216,34 @@ func (m Module) DeletePromoOrderUsage(ctx context.Context, id string) (string, e
 	return resp, err
 }
 
-func (m Module) BulkPromoOrderUsage(ctx context.Context, body io.Reader) error {
-	defer m.monitor.SetHistogram(time.Now(), "usecase.elastic.officialclient.bulk.promo.order.usage", nil)
+func (m Module) BulkPromoOrderUsage(ctx context.Context, body io.Reader) (err error) {
+	ctx, span := m.start(ctx, "BulkPromoOrderUsage", elasticEntity.OperationBulk)
+	defer m.record(ctx, span, time.Now(), elasticEntity.OperationBulk, "usecase.elastic.officialclient.bulk.promo.order.usage", &err)
 
-	err := m.usecase.elastic.ProcessBulk(ctx, body)
+	err = m.usecase.elastic.ProcessBulk(ctx, body)
 	if err != nil {
 		log.Error(err)
 	}
 
 	return err
 }
+
+func (m Module) start(ctx context.Context, method, operation string, attributes ...attribute.KeyValue) (context.Context, trace.Span) {
+	return tracing.Start(monitor.WithStatus(ctx), elasticEntity.ClientOfficialClient+"."+method, append(attributes,
+		tracing.AttributeClient.String(elasticEntity.ClientOfficialClient),
+		tracing.AttributeOperation.String(operation),
+		tracing.AttributeIndex.String(m.config.ElasticSearch.Index),
+	)...)
+}
+
+func (m Module) record(ctx context.Context, span trace.Span, start time.Time, operation, name string, err *error) {
+	m.monitor.SetHistogram(start, name, monitor.Call{
+		Client:    elasticEntity.ClientOfficialClient,
+		Operation: operation,
+		Index:     m.config.ElasticSearch.Index,
+		Status:    monitor.Status(ctx),
+		Err:       *err,
+	}.Tags())
+
+	tracing.End(ctx, span, *err)
+}
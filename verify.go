@@ -0,0 +1,97 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/elastic-fray/entity/benchmark"
+	elasticEntity "github.com/elastic-fray/entity/elastic"
+
+	"github.com/tokopedia/tdk/go/log"
+)
+
+func verify(args []string) {
+	var out string
+
+	flags := flag.NewFlagSet("verify", flag.ExitOnError)
+	configFlags(flags)
+	workload := newWorkloadFlags(flags, []string{elasticEntity.OperationSearch, elasticEntity.OperationCount})
+	flags.StringVar(&out, "out", "", "also write the verification as JSON to this file")
+	flags.Parse(args)
+
+	parameters := workload.load()
+
+	// faults only matter to the benchmark, both clients are compared healthy
+	setup(clients(parameters), false)
+
+	var (
+		runner        = newBenchmark()
+		verifications []benchmark.Verification
+		mismatched    bool
+	)
+
+	for _, parameter := range parameters {
+		verification, err := runner.Verify(Context, parameter)
+		if err != nil {
+			shutdown()
+			log.Fatal(err)
+		}
+
+		printVerification(verification)
+		verifications = append(verifications, verification)
+
+		for _, check := range verification.Checks {
+			mismatched = mismatched || !check.Match
+		}
+	}
+
+	if out != "" {
+		data, err := json.MarshalIndent(verifications, "", "  ")
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		if err = ioutil.WriteFile(out, data, 0644); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	shutdown()
+
+	if mismatched {
+		os.Exit(1)
+	}
+}
+
+func printVerification(verification benchmark.Verification) {
+	fmt.Printf("\n== %s\n", verification.Scenario)
+
+	for _, check := range verification.Checks {
+		status := "MATCH"
+		if !check.Match {
+			status = "MISMATCH"
+		}
+
+		fmt.Printf("%-8s %-5s %q", status, check.Operation, check.Query)
+		for _, total := range check.Totals {
+			fmt.Printf(" %s=%d", total.Client, total.Total)
+		}
+		fmt.Println()
+
+		if check.Error != "" {
+			fmt.Printf("    error: %s\n", check.Error)
+		}
+
+		for _, mismatch := range check.Mismatches {
+			fmt.Printf("    %s order_id=%d %s", mismatch.Client, mismatch.OrderID, mismatch.Reason)
+			if len(mismatch.Fields) > 0 {
+				fmt.Printf(": %s", strings.Join(mismatch.Fields, ", "))
+			}
+			fmt.Println()
+		}
+	}
+}